@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"maplestory-world-llms-txt/internal/crawler"
+)
+
+// browserFlags are the browser and pacing flags shared by the commands that
+// drive Chrome.
+type browserFlags struct {
+	head        bool
+	chromePath  string
+	remoteURL   string
+	userDataDir string
+	proxy       string
+	windowSize  string
+	userAgent   string
+	ignoreCert  bool
+
+	delay     time.Duration
+	timeout   time.Duration
+	rate      float64
+	burst     int
+	jitter    float64
+	hostRates []crawler.LimiterOption
+}
+
+// register defines the flags on fs.
+func (b *browserFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&b.head, "headless", true, "run headless Chrome")
+	fs.StringVar(&b.chromePath, "chrome-path", "", "Chrome executable to launch (default: auto-detect)")
+	fs.StringVar(&b.remoteURL, "remote-url", "", "attach to a running Chrome at this DevTools URL instead of launching one")
+	fs.StringVar(&b.userDataDir, "user-data-dir", "", "Chrome profile directory")
+	fs.StringVar(&b.proxy, "proxy", "", "proxy server, e.g. http://127.0.0.1:8080")
+	fs.StringVar(&b.windowSize, "window-size", "", "browser window size as WIDTHxHEIGHT, e.g. 1280x800")
+	fs.StringVar(&b.userAgent, "user-agent", crawler.DefaultUserAgent, "user agent (empty = Chrome's own)")
+	fs.BoolVar(&b.ignoreCert, "ignore-cert-errors", true, "ignore TLS certificate errors")
+
+	fs.DurationVar(&b.delay, "delay", 150*time.Millisecond, "delay between clicks")
+	fs.DurationVar(&b.timeout, "timeout", 120*time.Second, "overall timeout per target")
+	fs.Float64Var(&b.rate, "rate", 2, "max navigations and fetches per second per host (0 = unlimited)")
+	fs.IntVar(&b.burst, "burst", 4, "requests allowed in a burst above -rate")
+	fs.Func("host-rate", "per-host override as HOST=RATE[:BURST]; repeatable", func(v string) error {
+		opt, err := parseHostRate(v)
+		if err == nil {
+			b.hostRates = append(b.hostRates, opt)
+		}
+		return err
+	})
+	fs.Float64Var(&b.jitter, "jitter", 0.4, "log-normal spread of the click delay (0 = constant)")
+}
+
+// session returns an unopened browser session configured from the flags.
+func (b *browserFlags) session() *crawler.Session {
+	var width, height int
+	if b.windowSize != "" {
+		if _, err := fmt.Sscanf(b.windowSize, "%dx%d", &width, &height); err != nil {
+			log.Fatalf("invalid -window-size %q: want WIDTHxHEIGHT", b.windowSize)
+		}
+	}
+	return crawler.NewSession(
+		crawler.WithSessionHeadless(b.head),
+		crawler.WithExecPath(b.chromePath),
+		crawler.WithRemoteURL(b.remoteURL),
+		crawler.WithUserDataDir(b.userDataDir),
+		crawler.WithProxy(b.proxy),
+		crawler.WithWindowSize(width, height),
+		crawler.WithUserAgent(b.userAgent),
+		crawler.WithIgnoreCertErrors(b.ignoreCert),
+	)
+}
+
+// crawlerOptions returns the pacing options for a Crawler running in sess.
+func (b *browserFlags) crawlerOptions(sess *crawler.Session) []crawler.Option {
+	return []crawler.Option{
+		crawler.WithClickDelay(b.delay),
+		crawler.WithOverallTimeout(b.timeout),
+		crawler.WithSession(sess),
+		crawler.WithLimiter(crawler.NewLimiter(b.rate, b.burst, b.hostRates...)),
+		crawler.WithDelayJitter(b.jitter),
+	}
+}
+
+// parseHostRate parses a -host-rate value such as
+// "maplestoryworlds-creators.nexon.com=1:2".
+func parseHostRate(v string) (crawler.LimiterOption, error) {
+	host, spec, ok := strings.Cut(v, "=")
+	if !ok || host == "" {
+		return nil, fmt.Errorf("want HOST=RATE[:BURST], got %q", v)
+	}
+	rateStr, burstStr, hasBurst := strings.Cut(spec, ":")
+	rate, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate in %q: %w", v, err)
+	}
+	burst := 1
+	if hasBurst {
+		if burst, err = strconv.Atoi(burstStr); err != nil {
+			return nil, fmt.Errorf("invalid burst in %q: %w", v, err)
+		}
+	}
+	return crawler.WithHostLimit(host, rate, burst), nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"log"
+	"os"
+
+	"maplestory-world-llms-txt/internal/chunk"
+	"maplestory-world-llms-txt/internal/document"
+)
+
+// runChunk splits the converted documents of one or more JSON corpora into
+// token-bounded chunks and writes them as JSONL.
+//
+//	crawler chunk [-max-tokens 512] [-overlap 64] [-out chunks.jsonl] docs/en/reference.json ...
+func runChunk(args []string) {
+	var (
+		maxTokens int
+		overlap   int
+		out       string
+	)
+
+	fs := flag.NewFlagSet("chunk", flag.ExitOnError)
+	fs.IntVar(&maxTokens, "max-tokens", 512, "token budget per chunk")
+	fs.IntVar(&overlap, "overlap", 64, "tokens of trailing text repeated from the previous chunk of a section")
+	fs.StringVar(&out, "out", "", "output JSONL file (default stdout)")
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		log.Fatalf("usage: crawler chunk [flags] corpus.json...")
+	}
+
+	c := chunk.NewChunker(
+		chunk.WithMaxTokens(maxTokens),
+		chunk.WithOverlap(overlap),
+	)
+
+	w := os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			log.Fatalf("create %s: %v", out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+
+	total := 0
+	for _, path := range fs.Args() {
+		docs, err := document.LoadJSON(path)
+		if err != nil {
+			log.Fatalf("load %s: %v", path, err)
+		}
+		for _, d := range docs {
+			chunks := c.Split(d.Title, d.URL, d.Breadcrumb, d.Markdown)
+			if err := chunk.WriteJSONL(bw, chunks); err != nil {
+				log.Fatalf("write chunks: %v", err)
+			}
+			total += len(chunks)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		log.Fatalf("flush: %v", err)
+	}
+	log.Printf("wrote %d chunks", total)
+}
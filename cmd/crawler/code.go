@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/markdown"
+)
+
+// codeSample is one fenced code block of a document, as written to the
+// per-document code extract.
+type codeSample struct {
+	ID    string `json:"id"`
+	URL   string `json:"url"`
+	Title string `json:"title"`
+	markdown.CodeBlock
+}
+
+// codeSamples returns the code blocks of docs, optionally limited to one
+// language ("" keeps all, "none" keeps untagged blocks).
+func codeSamples(docs []document.Document, lang string) []codeSample {
+	var out []codeSample
+	for _, d := range docs {
+		for _, b := range markdown.CodeBlocks(d.Markdown) {
+			if lang != "" && b.Lang != lang && !(lang == "none" && b.Lang == "") {
+				continue
+			}
+			out = append(out, codeSample{ID: d.ID, URL: d.URL, Title: d.Title, CodeBlock: b})
+		}
+	}
+	return out
+}
+
+func writeCodeJSONL(w io.Writer, samples []codeSample) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, s := range samples {
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeCodeSamples returns the code extract of docs as JSONL, with the
+// number of samples.
+func encodeCodeSamples(docs []document.Document) ([]byte, int, error) {
+	samples := codeSamples(docs, "")
+	var buf bytes.Buffer
+	if err := writeCodeJSONL(&buf, samples); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), len(samples), nil
+}
+
+// codeFileName returns the code extract path stored next to a Markdown
+// output, e.g. docs/en/api.md -> docs/en/api.code.jsonl.
+func codeFileName(mdFileName string) string {
+	return strings.TrimSuffix(mdFileName, filepath.Ext(mdFileName)) + ".code.jsonl"
+}
+
+// runCode extracts the fenced code samples of one or more JSON corpora, as
+// JSONL or as one source file per sample (for linters and snippet libraries).
+//
+//	crawler code [-lang lua] [-out samples.jsonl | -dir snippets/] docs/en/*.json
+func runCode(args []string) {
+	var (
+		lang string
+		out  string
+		dir  string
+	)
+
+	fs := flag.NewFlagSet("code", flag.ExitOnError)
+	fs.StringVar(&lang, "lang", "", `keep only samples of this language ("none" = untagged)`)
+	fs.StringVar(&out, "out", "", "output JSONL file (default stdout)")
+	fs.StringVar(&dir, "dir", "", "write each sample to its own file in this directory instead of JSONL")
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		log.Fatalf("usage: crawler code [flags] corpus.json...")
+	}
+
+	samples := codeSamples(loadDocuments(fs.Args()), lang)
+
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			log.Fatalf("create %s: %v", dir, err)
+		}
+		seen := make(map[string]int)
+		for _, s := range samples {
+			base := strings.ReplaceAll(s.ID, "/", "_")
+			seen[base]++
+			name := fmt.Sprintf("%s_%02d.%s", base, seen[base], sampleExt(s.Lang))
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(s.Code+"\n"), 0o644); err != nil {
+				log.Fatalf("write %s: %v", name, err)
+			}
+		}
+		log.Printf("wrote %d code samples to %s", len(samples), dir)
+		return
+	}
+
+	w := os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			log.Fatalf("create %s: %v", out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	if err := writeCodeJSONL(bw, samples); err != nil {
+		log.Fatalf("write samples: %v", err)
+	}
+	if err := bw.Flush(); err != nil {
+		log.Fatalf("flush: %v", err)
+	}
+	log.Printf("wrote %d code samples", len(samples))
+}
+
+// sampleExt returns the file extension for a sample of the given language.
+func sampleExt(lang string) string {
+	switch lang {
+	case "":
+		return "txt"
+	case "javascript":
+		return "js"
+	default:
+		return lang
+	}
+}
@@ -1,17 +1,26 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"log/slog"
+	"maps"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
-	"time"
+	"slices"
+	"strings"
+	"syscall"
 
+	"maplestory-world-llms-txt/internal/convert"
 	"maplestory-world-llms-txt/internal/crawler"
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/markdown"
+	"maplestory-world-llms-txt/internal/validate"
 )
 
 var (
@@ -22,40 +31,135 @@ var (
 		/* API docs */
 		"https://maplestoryworlds-creators.nexon.com/ko/apiReference/How-to-use-API-Reference": "docs/kr/api.md",
 		"https://maplestoryworlds-creators.nexon.com/en/apiReference/How-to-use-API-Reference": "docs/en/api.md",
+		/* Enum pages, which the API navigation tree does not include */
+		"https://maplestoryworlds-creators.nexon.com/ko/apiReference/Enums/KeyboardKey": "docs/kr/enums.md",
+		"https://maplestoryworlds-creators.nexon.com/en/apiReference/Enums/KeyboardKey": "docs/en/enums.md",
 	}
 )
 
-func main() {
-	var (
-		head    bool
-		delay   time.Duration
-		limit   int
-		timeout time.Duration
-	)
-
-	flag.BoolVar(&head, "headless", true, "run headless Chrome")
-	flag.DurationVar(&delay, "delay", 150*time.Millisecond, "delay between clicks")
-	flag.IntVar(&limit, "limit", 0, "max number of documents to crawl (0 = no limit)")
-	flag.DurationVar(&timeout, "timeout", 120*time.Second, "overall timeout for crawling")
-	flag.Parse()
+// commands maps subcommand names to their entry points. Running the binary
+// without a known subcommand crawls the default targets.
+var commands = map[string]func(args []string){
+	"chunk":     runChunk,
+	"code":      runCode,
+	"export":    runExport,
+	"glossary":  runGlossary,
+	"index":     runIndex,
+	"publish":   runPublish,
+	"render":    runRender,
+	"search":    runSearch,
+	"sections":  runSections,
+	"serve":     runServe,
+	"serve-mcp": runServeMCP,
+	"sqlite":    runSQLite,
+	"tree":      runTree,
+	"validate":  runValidate,
+}
 
+func main() {
 	// Configure default slog logger (text to stderr, Info level)
 	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})
 	slog.SetDefault(slog.New(handler))
 
-	c := crawler.NewCrawler(
-		crawler.WithClickDelay(delay),
-		crawler.WithLimit(limit),
-		crawler.WithOverallTimeout(timeout),
-		crawler.WithHeadless(head),
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			cmd(os.Args[2:])
+			return
+		}
+	}
+	runCrawl(os.Args[1:])
+}
+
+// runCrawl crawls every target, converts each document to Markdown and writes
+// the concatenated Markdown plus a JSON corpus of the converted documents.
+func runCrawl(args []string) {
+	var (
+		browser    browserFlags
+		limit      int
+		reportPath string
+		api        apiFlags
+		outline    outlineFlags
+		timestamps bool
+		out        outputs
 	)
 
-	for targetURL, outFileName := range targets {
-		docs, err := c.Run(targetURL)
-		if err != nil {
+	fs := flag.NewFlagSet("crawl", flag.ExitOnError)
+	browser.register(fs)
+	fs.IntVar(&limit, "limit", 0, "max number of documents to crawl (0 = no limit)")
+	fs.StringVar(&reportPath, "report", "crawl-report.json", "write the per-target crawl report as JSON to this file (empty = off)")
+	api.register(fs)
+	outline.register(fs)
+	fs.BoolVar(&timestamps, "timestamps", false, "keep fetch times and durations in the JSON corpus (off keeps regenerated output byte-identical)")
+	fs.BoolVar(&out.check, "check", false, "write nothing; fail if regenerating would change the committed output files")
+	_ = fs.Parse(args)
+
+	// Ctrl-C stops the crawl; the documents collected so far are still
+	// converted and written before exiting
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// One browser serves every target; each crawl runs in its own tab
+	sess := browser.session()
+	if err := sess.Open(ctx); err != nil {
+		log.Fatalf("open browser: %v", err)
+	}
+	defer sess.Close()
+
+	c := crawler.NewCrawler(append(browser.crawlerOptions(sess), crawler.WithLimit(limit))...)
+
+	var reports []*crawler.Report
+	// finish summarizes every run so far and writes the report file, except
+	// in check mode, which writes nothing. It is called explicitly because
+	// log.Fatalf skips deferred calls.
+	finish := func() {
+		for _, r := range reports {
+			log.Printf("crawl report for %s: %s; navigation tree of %d nodes", r.StartURL, r.Summary(), r.Tree.Nodes)
+			for _, t := range r.Failures() {
+				log.Printf("  %s %s %s: %s", t.Outcome, t.XPath, t.URL, t.Error)
+			}
+		}
+		if reportPath != "" && len(reports) > 0 && !out.check {
+			if err := crawler.SaveReports(reportPath, reports); err != nil {
+				log.Printf("write crawl report: %v", err)
+			} else {
+				log.Printf("wrote crawl report to %s", reportPath)
+			}
+		}
+	}
+
+	// Targets run in a fixed order so logs and reports are comparable
+	for _, targetURL := range slices.Sorted(maps.Keys(targets)) {
+		outFileName := targets[targetURL]
+		if ctx.Err() != nil {
+			break
+		}
+		docs, report, err := c.RunContext(ctx, targetURL)
+		reports = append(reports, report)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			log.Printf("interrupted: flushing %d documents collected from %q", len(docs), targetURL)
+			if len(docs) == 0 {
+				continue
+			}
+		case err != nil:
+			finish()
+			_ = sess.Close()
 			log.Fatalf("crawler error: %v", err)
+		default:
+			log.Printf("crawled %d documents from %q", len(docs), targetURL)
+		}
+
+		// Order by navigation position rather than click sequence, and drop
+		// the fetch timings unless asked for, so identical pages produce
+		// identical files
+		if report.Nav != nil {
+			report.Nav.SortDocuments(docs)
+		}
+		if !timestamps {
+			for i := range docs {
+				docs[i].Fetch = document.Fetch{}
+			}
 		}
-		log.Printf("crawled %d documents from %q", len(docs), targetURL)
 
 		// Create temp dir to store individual HTML doc files
 		tmpDir, err := os.MkdirTemp("", "crawler_docs_*")
@@ -64,7 +168,31 @@ func main() {
 		}
 		defer os.RemoveAll(tmpDir)
 
-		paths, err := crawler.SaveDocumentFile(docs, tmpDir)
+		// Replace images with textual placeholders before conversion so badges and
+		// screenshots stay meaningful in text-only output
+		prepared := make([]document.Document, len(docs))
+		for i, d := range docs {
+			links, err := convert.Links(d.HTML, d.URL)
+			if err != nil {
+				log.Fatalf("extract links for %s: %v", d.URL, err)
+			}
+			docs[i].Links = links
+
+			html, err := convert.RewriteImages(d.HTML)
+			if err != nil {
+				log.Fatalf("rewrite images for %s: %v", d.URL, err)
+			}
+
+			// Tag code containers with their language so mdream emits
+			// fenced, indentation-preserving blocks
+			if html, err = convert.TagCodeBlocks(html); err != nil {
+				log.Fatalf("tag code blocks for %s: %v", d.URL, err)
+			}
+			d.HTML = html
+			prepared[i] = d
+		}
+
+		paths, err := crawler.SaveDocumentFile(prepared, tmpDir)
 		if err != nil {
 			log.Fatalf("SaveDocumentFile error: %v", err)
 		}
@@ -76,46 +204,73 @@ func main() {
 		}
 		defer os.RemoveAll(mdTmpDir)
 
-		mdParts := make([]string, 0, len(paths))
 		for i, p := range paths {
 			partOut := filepath.Join(mdTmpDir, fmt.Sprintf("%03d_%s", i, filepath.Base(outFileName)))
 			if err := mdream(p, partOut); err != nil {
 				log.Fatalf("mdream error for %s: %v", p, err)
 			}
-			mdParts = append(mdParts, partOut)
-		}
 
-		// Concatenate all generated Markdown parts into the final output file
-		outF, err := os.OpenFile(outFileName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
-		if err != nil {
-			log.Fatalf("open %s: %v", outFileName, err)
-		}
-		for i, p := range mdParts {
-			inF, err := os.Open(p)
+			// Tag the fences mdream left without a language, then keep the
+			// converted Markdown on the document for the JSON corpus
+			raw, err := os.ReadFile(partOut)
 			if err != nil {
-				_ = outF.Close()
-				log.Fatalf("open %s: %v", p, err)
-			}
-			if _, err := io.Copy(outF, inF); err != nil {
-				_ = inF.Close()
-				_ = outF.Close()
-				log.Fatalf("copy from %s: %v", p, err)
+				log.Fatalf("read %s: %v", partOut, err)
 			}
-			_ = inF.Close()
-			// Separate documents with a newline to preserve previous behavior
-			if i < len(mdParts)-1 {
-				if _, err := outF.WriteString("\n"); err != nil {
-					_ = outF.Close()
-					log.Fatalf("write newline: %v", err)
-				}
+			docs[i].Markdown = markdown.Normalize(markdown.TagFences(string(raw), convert.DetectLanguage))
+		}
+
+		// Lint the converted documents against the previous crawl before it is
+		// overwritten; problems are reported but do not stop the run
+		corpusPath := corpusFileName(outFileName)
+		var prev []document.Document
+		if _, err := os.Stat(corpusPath); err == nil {
+			if prev, err = document.LoadJSON(corpusPath); err != nil {
+				log.Printf("previous corpus %s unreadable, skipping comparison: %v", corpusPath, err)
 			}
 		}
-		if err := outF.Close(); err != nil {
-			log.Fatalf("close %s: %v", outFileName, err)
+		for _, is := range validate.NewValidator(validate.WithPrevious(prev)).Check(docs) {
+			slog.Warn("validate", slog.String("issue", is.String()))
+		}
+
+		data, err := document.EncodeJSON(docs)
+		if err != nil {
+			log.Fatalf("encode corpus: %v", err)
+		}
+		if err := out.write(corpusPath, data); err != nil {
+			log.Fatalf("write %s: %v", corpusPath, err)
+		}
+		log.Printf("wrote %d documents to %s", len(docs), corpusPath)
+
+		codePath := codeFileName(outFileName)
+		code, n, err := encodeCodeSamples(docs)
+		if err != nil {
+			log.Fatalf("encode code samples: %v", err)
+		}
+		if err := out.write(codePath, code); err != nil {
+			log.Fatalf("write %s: %v", codePath, err)
+		}
+		log.Printf("wrote %d code samples to %s", n, codePath)
+
+		// Assemble the documents into the final output file. The corpus keeps
+		// mdream's member tables, which apiref parses; only the published
+		// Markdown uses the selected API layout. Flattening needs every class
+		// of the target, so this runs after conversion.
+		md := assembleMarkdown(docs, api, outline)
+		if err := out.write(outFileName, []byte(md)); err != nil {
+			log.Fatalf("write %s: %v", outFileName, err)
 		}
-		log.Printf("wrote concatenated markdown to %s (from %d parts in %s)", outFileName, len(mdParts), filepath.Base(mdTmpDir))
+		log.Printf("wrote assembled markdown to %s (%d documents)", outFileName, len(docs))
 	}
+	finish()
+	out.done()
 }
+
+// corpusFileName returns the JSON corpus path stored next to a Markdown output,
+// e.g. docs/en/api.md -> docs/en/api.json.
+func corpusFileName(mdFileName string) string {
+	return strings.TrimSuffix(mdFileName, filepath.Ext(mdFileName)) + ".json"
+}
+
 func mdream(inputFileName, outFileName string) error {
 	inputFile, err := os.Open(inputFileName)
 	if err != nil {
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"maplestory-world-llms-txt/internal/apiexport"
+)
+
+// runExport writes the API model of a corpus as TypeScript declarations, as
+// a JSON Schema, or as a type cross-reference index in JSON or Markdown.
+//
+//	crawler export [-format dts|schema|types|types-md] [-lang en] [-out msw.d.ts] [corpus.json...]
+func runExport(args []string) {
+	var (
+		format string
+		lang   string
+		out    string
+	)
+
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.StringVar(&format, "format", "dts", "output format: dts (TypeScript declarations), schema (JSON Schema), types (type cross-reference index as JSON) or types-md (the index as a Markdown page)")
+	fs.StringVar(&lang, "lang", "en", "language of the API pages to export")
+	fs.StringVar(&out, "out", "", "output file (default stdout)")
+	_ = fs.Parse(args)
+
+	c := loadCorpus(fs.Args())
+	classes := c.Classes(lang)
+	if len(classes) == 0 {
+		log.Fatalf("no API classes found for language %q", lang)
+	}
+
+	var data []byte
+	switch format {
+	case "dts":
+		data = []byte(apiexport.DTS(classes))
+	case "schema":
+		var err error
+		if data, err = apiexport.MarshalSchema(classes); err != nil {
+			log.Fatalf("encode schema: %v", err)
+		}
+	case "types":
+		var err error
+		if data, err = json.MarshalIndent(apiexport.BuildIndex(classes, c.Enums(lang)), "", "  "); err != nil {
+			log.Fatalf("encode type index: %v", err)
+		}
+		data = append(data, '\n')
+	case "types-md":
+		data = []byte(apiexport.BuildIndex(classes, c.Enums(lang)).Markdown())
+	default:
+		log.Fatalf("unknown -format %q (want dts, schema, types or types-md)", format)
+	}
+
+	if out == "" {
+		if _, err := os.Stdout.Write(data); err != nil {
+			log.Fatalf("write: %v", err)
+		}
+		return
+	}
+	if err := os.WriteFile(out, data, 0o644); err != nil {
+		log.Fatalf("write %s: %v", out, err)
+	}
+	log.Printf("wrote %d classes to %s", len(classes), out)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/glossary"
+)
+
+// runGlossary extracts a Korean↔English glossary from the English and Korean
+// corpora, for review and for use in prompts.
+//
+//	crawler glossary [-format tsv|json] [-out glossary.tsv] [corpus.json...]
+func runGlossary(args []string) {
+	var (
+		format string
+		out    string
+	)
+
+	fs := flag.NewFlagSet("glossary", flag.ExitOnError)
+	fs.StringVar(&format, "format", "tsv", "output format: tsv or json")
+	fs.StringVar(&out, "out", "", "output file (default stdout)")
+	_ = fs.Parse(args)
+	if format != "tsv" && format != "json" {
+		log.Fatalf("unknown -format %q (want tsv or json)", format)
+	}
+
+	var en, ko []document.Document
+	for _, d := range loadCorpus(fs.Args()).Docs {
+		switch d.Lang {
+		case "en":
+			en = append(en, d)
+		case "ko":
+			ko = append(ko, d)
+		}
+	}
+	pairs := glossary.Align(en, ko)
+	byPosition := 0
+	for _, p := range pairs {
+		if p.ByPosition {
+			byPosition++
+		}
+	}
+	entries := glossary.Extract(pairs)
+
+	w := os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			log.Fatalf("create %s: %v", out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	var err error
+	if format == "json" {
+		enc := json.NewEncoder(bw)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(entries)
+	} else {
+		err = glossary.WriteTSV(bw, entries)
+	}
+	if err != nil {
+		log.Fatalf("write glossary: %v", err)
+	}
+	if err := bw.Flush(); err != nil {
+		log.Fatalf("flush: %v", err)
+	}
+	log.Printf("wrote %d terms from %d document pairs (%d paired by position)", len(entries), len(pairs), byPosition)
+}
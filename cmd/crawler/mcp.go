@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+
+	"maplestory-world-llms-txt/internal/corpus"
+	"maplestory-world-llms-txt/internal/mcp"
+)
+
+// runServeMCP serves the corpus to MCP clients over stdio. Logs go to stderr
+// because stdout carries the protocol.
+//
+//	crawler serve-mcp [corpus.json...]
+func runServeMCP(args []string) {
+	fs := flag.NewFlagSet("serve-mcp", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	c := loadCorpus(fs.Args())
+	log.Printf("serving %d documents over MCP (stdio)", len(c.Docs))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := mcp.NewServer(c).Serve(ctx, os.Stdin, os.Stdout); err != nil && ctx.Err() == nil {
+		log.Fatalf("mcp server: %v", err)
+	}
+}
+
+// loadCorpus loads the given corpus files, or the default docs/<lang>/*.json
+// outputs of the crawl command when none are given.
+func loadCorpus(paths []string) *corpus.Corpus {
+	if len(paths) == 0 {
+		var err error
+		if paths, err = corpus.DefaultPaths(); err != nil {
+			log.Fatalf("find corpora: %v", err)
+		}
+		if len(paths) == 0 {
+			log.Fatalf("no corpus files found under docs/; run the crawler first or pass corpus.json paths")
+		}
+	}
+	c, err := corpus.Load(paths...)
+	if err != nil {
+		log.Fatalf("load corpus: %v", err)
+	}
+	return c
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+)
+
+// outputs writes generated files. In check mode nothing is written; each file
+// is compared with the one on disk instead, and done fails the run if any
+// would change, so CI can verify that committed output is up to date.
+type outputs struct {
+	check   bool
+	changed []string
+}
+
+// write writes data to path, or in check mode records path when its current
+// content differs from data.
+func (o *outputs) write(path string, data []byte) error {
+	if !o.check {
+		return os.WriteFile(path, data, 0o644)
+	}
+	old, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err != nil || !bytes.Equal(old, data) {
+		o.changed = append(o.changed, path)
+	}
+	return nil
+}
+
+// done reports the outcome of a check and exits nonzero when a file would
+// change. It does nothing outside check mode.
+func (o *outputs) done() {
+	if !o.check {
+		return
+	}
+	if len(o.changed) > 0 {
+		log.Fatalf("check failed: regenerating would change %d files: %s", len(o.changed), strings.Join(o.changed, ", "))
+	}
+	log.Printf("check passed: output is up to date")
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/publish"
+)
+
+// runPublish writes every language of a corpus as an EPUB 3 book and as a
+// single self-contained HTML page, guides first and then the API reference,
+// in the order of the crawl. Images are embedded from a local image cache,
+// <out-dir>/assets unless -assets names another, filled from the CDN with
+// -fetch-images; those missing from it stay remote, which is logged.
+//
+//	crawler publish [-format epub,html] [-out-dir dist] [-title "..."] [-assets dir] [-fetch-images] [-check] [corpus.json...]
+func runPublish(args []string) {
+	var (
+		api     apiFlags
+		formats string
+		outDir  string
+		title   string
+		assets  string
+		fetch   bool
+		out     outputs
+	)
+
+	fs := flag.NewFlagSet("publish", flag.ExitOnError)
+	api.register(fs)
+	fs.StringVar(&formats, "format", "epub,html", "comma-separated output formats: epub and html")
+	fs.StringVar(&outDir, "out-dir", "dist", "directory receiving <lang>.epub and <lang>.html")
+	fs.StringVar(&title, "title", "", "book title (default per language)")
+	fs.StringVar(&assets, "assets", "", "local image cache whose images are embedded (default <out-dir>/assets); images missing from it are links in the EPUB and load from the CDN in HTML")
+	fs.BoolVar(&fetch, "fetch-images", false, "download images missing from the image cache into it")
+	fs.BoolVar(&out.check, "check", false, "write nothing; fail if a published file would change")
+	_ = fs.Parse(args)
+
+	var epub, html bool
+	for _, f := range strings.Split(formats, ",") {
+		switch strings.TrimSpace(f) {
+		case "epub":
+			epub = true
+		case "html":
+			html = true
+		default:
+			log.Fatalf("unknown -format %q (want epub, html or both)", f)
+		}
+	}
+
+	if assets == "" {
+		assets = filepath.Join(outDir, "assets")
+	}
+	var assetOpts []publish.AssetOption
+	if fetch {
+		assetOpts = append(assetOpts, publish.WithFetch(&http.Client{Timeout: time.Minute}))
+	}
+	cache := publish.NewAssets(assets, assetOpts...)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c := loadCorpus(fs.Args())
+	if !out.check {
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			log.Fatalf("create %s: %v", outDir, err)
+		}
+	}
+	for _, lang := range c.Langs() {
+		// Guides come before the API reference whatever the corpus order
+		var docs []document.Document
+		for _, isAPI := range []bool{false, true} {
+			for _, d := range c.Docs {
+				if d.Lang == lang && (d.Kind == document.KindAPI) == isAPI {
+					docs = append(docs, d)
+				}
+			}
+		}
+		name := lang
+		if name == "" {
+			name = "docs"
+		}
+		images, missing, err := cache.Load(ctx, docs)
+		if err != nil {
+			log.Fatalf("load %s images: %v", name, err)
+		}
+		log.Printf("embedding %d %s images from %s (%d missing)", len(images), name, assets, len(missing))
+		if html && len(missing) > 0 {
+			slog.Warn("HTML page is not self-contained; rerun with -fetch-images to cache the missing images",
+				slog.String("file", name+".html"), slog.Int("remote_images", len(missing)), slog.String("assets", assets))
+		}
+		opts := []publish.Option{publish.WithTitle(title), publish.WithImages(images)}
+		book := publish.NewBook(lang, parts(docs, api.renderer(docs)), opts...)
+
+		var buf bytes.Buffer
+		if epub {
+			if err := book.WriteEPUB(&buf); err != nil {
+				log.Fatalf("build %s EPUB: %v", name, err)
+			}
+			path := filepath.Join(outDir, name+".epub")
+			if err := out.write(path, buf.Bytes()); err != nil {
+				log.Fatalf("write %s: %v", path, err)
+			}
+			buf.Reset()
+		}
+		if html {
+			if err := book.WriteHTML(&buf); err != nil {
+				log.Fatalf("build %s HTML: %v", name, err)
+			}
+			path := filepath.Join(outDir, name+".html")
+			if err := out.write(path, buf.Bytes()); err != nil {
+				log.Fatalf("write %s: %v", path, err)
+			}
+		}
+		log.Printf("published %d %s documents to %s", len(docs), name, outDir)
+	}
+	out.done()
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"maplestory-world-llms-txt/internal/apiref"
+	"maplestory-world-llms-txt/internal/assemble"
+	"maplestory-world-llms-txt/internal/document"
+)
+
+// defaultAPIStyle is the layout the crawl writes docs/*/api.md in. Every
+// command defaults to it, so render -check agrees with committed output.
+const defaultAPIStyle = apiref.StyleMdream
+
+// apiFlags holds the flags that choose how API pages are written, shared by
+// the crawl and the render and publish commands. Values are checked while
+// parsing so a typo fails before a long crawl rather than after it.
+type apiFlags struct {
+	style   apiref.Style
+	inherit apiref.Inheritance
+}
+
+func (f *apiFlags) register(fs *flag.FlagSet) {
+	f.style, f.inherit = defaultAPIStyle, apiref.InheritGrouped
+	fs.Func("api-style", fmt.Sprintf("how API members are written: mdream (one table each), headings or table (default %s)", defaultAPIStyle), func(v string) error {
+		s, err := apiref.ParseStyle(v)
+		f.style = s
+		return err
+	})
+	fs.Func("inherit", "how inherited API members are written: grouped (as on the page, default), flatten (once, on the base class) or expand (inline, annotated with their class)", func(v string) error {
+		in, err := apiref.ParseInheritance(v)
+		f.inherit = in
+		return err
+	})
+}
+
+// renderer builds an API renderer from the flags. Flattening drops inherited
+// members only for base classes that have a page among docs.
+func (f *apiFlags) renderer(docs []document.Document) *apiref.Renderer {
+	var names []string
+	for _, d := range docs {
+		if c, ok := apiref.Parse(d.URL, d.Markdown); ok {
+			names = append(names, c.Name)
+		}
+	}
+	return apiref.NewRenderer(
+		apiref.WithStyle(f.style),
+		apiref.WithInheritance(f.inherit),
+		apiref.WithKnownClasses(names),
+	)
+}
+
+// parts pairs every document with its Markdown as rendered by r.
+func parts(docs []document.Document, r *apiref.Renderer) []assemble.Part {
+	out := make([]assemble.Part, len(docs))
+	for i, d := range docs {
+		out[i] = assemble.Part{Doc: d, Markdown: r.Markdown(d.URL, d.Markdown)}
+	}
+	return out
+}
+
+// outlineFlags holds the flags that shape the concatenated Markdown file,
+// shared by the crawl and the render command.
+type outlineFlags struct {
+	toc      bool
+	tocDepth int
+}
+
+func (f *outlineFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.toc, "toc", true, "start the concatenated file with a table of contents")
+	fs.IntVar(&f.tocDepth, "toc-depth", 0, "section levels below each document title listed in the table of contents")
+}
+
+func (f *outlineFlags) assembler() *assemble.Assembler {
+	return assemble.NewAssembler(assemble.WithTOC(f.toc), assemble.WithTOCDepth(f.tocDepth))
+}
+
+// assembleMarkdown concatenates docs into one Markdown file the way the crawl
+// writes it.
+func assembleMarkdown(docs []document.Document, api apiFlags, outline outlineFlags) string {
+	return outline.assembler().Assemble(parts(docs, api.renderer(docs)))
+}
+
+// runRender rewrites the Markdown of a JSON corpus in another API layout
+// without crawling again, assembling the documents like the crawl does. With
+// -check it writes nothing and fails if -out would change, which verifies
+// committed Markdown against its corpus without network access.
+//
+//	crawler render [-api-style headings] [-inherit flatten] [-toc=false] [-check] [-out docs/en/api.md] docs/en/api.json ...
+func runRender(args []string) {
+	var (
+		api     apiFlags
+		outline outlineFlags
+		outPath string
+		out     outputs
+	)
+
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	api.register(fs)
+	outline.register(fs)
+	fs.StringVar(&outPath, "out", "", "output Markdown file (default stdout)")
+	fs.BoolVar(&out.check, "check", false, "write nothing; fail if the -out file would change")
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		log.Fatalf("usage: crawler render [flags] corpus.json...")
+	}
+	if out.check && outPath == "" {
+		log.Fatalf("-check needs -out")
+	}
+
+	docs := loadDocuments(fs.Args())
+	md := assembleMarkdown(docs, api, outline)
+	if outPath == "" {
+		if _, err := os.Stdout.WriteString(md); err != nil {
+			log.Fatalf("write: %v", err)
+		}
+	} else if err := out.write(outPath, []byte(md)); err != nil {
+		log.Fatalf("write %s: %v", outPath, err)
+	}
+	log.Printf("rendered %d documents (%s style, %s inheritance)", len(docs), api.style, api.inherit)
+	out.done()
+}
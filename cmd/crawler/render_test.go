@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/document"
+)
+
+// TestMain runs a subcommand in place of the tests when the test binary is
+// re-executed by runCommand, since commands exit through log.Fatalf.
+func TestMain(m *testing.M) {
+	if name := os.Getenv("CRAWLER_TEST_COMMAND"); name != "" {
+		args := os.Args[1:]
+		if i := slices.Index(args, "--"); i >= 0 {
+			args = args[i+1:]
+		}
+		commands[name](args)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runCommand runs a subcommand in a child process and returns its error,
+// non-nil when it exits nonzero.
+func runCommand(t *testing.T, name string, args ...string) error {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], append([]string{"--"}, args...)...)
+	cmd.Env = append(os.Environ(), "CRAWLER_TEST_COMMAND="+name)
+	out, err := cmd.CombinedOutput()
+	t.Logf("%s: %s", name, out)
+	return err
+}
+
+const apiPage = `# AIChaseComponent
+
+Chases a target.
+
+# Properties
+
+| float DetectionRange |
+| --- |
+| Range of trace detection. |
+
+##### inherited from Component:
+
+| boolean Enable [Sync] [HideFromInspector] |
+| --- |
+| Checks whether Component is activated or not. |
+`
+
+func TestRenderCheckMatchesCrawlOutput(t *testing.T) {
+	dir := t.TempDir()
+	doc := document.New("AIChaseComponent", "https://maplestoryworlds-creators.nexon.com/en/apiReference/Components/AIChaseComponent", "")
+	doc.Breadcrumb = []string{"Components"}
+	doc.Markdown = apiPage
+	docs := []document.Document{doc}
+
+	corpusPath := filepath.Join(dir, "api.json")
+	data, err := document.EncodeJSON(docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(corpusPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Write the Markdown with the crawl's own flag defaults
+	var (
+		api     apiFlags
+		outline outlineFlags
+	)
+	fs := flag.NewFlagSet("crawl", flag.ContinueOnError)
+	api.register(fs)
+	outline.register(fs)
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	mdPath := filepath.Join(dir, "api.md")
+	if err := os.WriteFile(mdPath, []byte(assembleMarkdown(docs, api, outline)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runCommand(t, "render", "-check", "-out", mdPath, corpusPath); err != nil {
+		t.Fatalf("render -check must accept crawl output: %v", err)
+	}
+	if err := runCommand(t, "render", "-check", "-api-style", "headings", "-out", mdPath, corpusPath); err == nil {
+		t.Fatal("render -check must fail when the output would change")
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/chunk"
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/search"
+)
+
+const defaultIndexPath = "docs/search-index.json"
+
+// runIndex builds a BM25 index from one or more JSON corpora.
+//
+//	crawler index [-out docs/search-index.json] [-chunks] docs/en/api.json ...
+func runIndex(args []string) {
+	var (
+		out       string
+		chunks    bool
+		maxTokens int
+	)
+
+	fs := flag.NewFlagSet("index", flag.ExitOnError)
+	fs.StringVar(&out, "out", defaultIndexPath, "index file to write")
+	fs.BoolVar(&chunks, "chunks", false, "index chunks instead of whole documents")
+	fs.IntVar(&maxTokens, "max-tokens", 512, "token budget per chunk when -chunks is set")
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		log.Fatalf("usage: crawler index [flags] corpus.json...")
+	}
+
+	ix := search.NewIndex()
+	c := chunk.NewChunker(chunk.WithMaxTokens(maxTokens))
+	for _, path := range fs.Args() {
+		docs, err := document.LoadJSON(path)
+		if err != nil {
+			log.Fatalf("load %s: %v", path, err)
+		}
+		for _, d := range docs {
+			if !chunks {
+				ix.Add(search.Entry{ID: d.ID, Title: d.Title, URL: d.URL, Text: d.Markdown})
+				continue
+			}
+			for _, ch := range c.Split(d.Title, d.URL, d.Breadcrumb, d.Markdown) {
+				ix.Add(search.Entry{ID: ch.ID, Title: strings.Join(ch.Breadcrumb, " > "), URL: d.URL, Text: ch.Text})
+			}
+		}
+	}
+	if err := ix.Save(out); err != nil {
+		log.Fatalf("save index: %v", err)
+	}
+	log.Printf("indexed %d entries into %s", ix.Len(), out)
+}
+
+// runSearch queries an index built by runIndex and prints ranked results.
+//
+//	crawler search [-index docs/search-index.json] [-k 10] [-json] query words...
+func runSearch(args []string) {
+	var (
+		indexPath string
+		k         int
+		asJSON    bool
+	)
+
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	fs.StringVar(&indexPath, "index", defaultIndexPath, "index file built by the index command")
+	fs.IntVar(&k, "k", 10, "number of results")
+	fs.BoolVar(&asJSON, "json", false, "print results as JSON")
+	_ = fs.Parse(args)
+	query := strings.Join(fs.Args(), " ")
+	if strings.TrimSpace(query) == "" {
+		log.Fatalf("usage: crawler search [flags] query")
+	}
+
+	ix, err := search.Load(indexPath)
+	if err != nil {
+		log.Fatalf("load index: %v", err)
+	}
+	results := ix.Search(query, k)
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			log.Fatalf("encode results: %v", err)
+		}
+		return
+	}
+	for i, r := range results {
+		fmt.Printf("%2d. %s (%.2f)\n    %s\n    %s\n", i+1, r.Title, r.Score, r.URL, r.Snippet)
+	}
+	if len(results) == 0 {
+		fmt.Println("no results")
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"log"
+	"os"
+
+	"maplestory-world-llms-txt/internal/corpus"
+	"maplestory-world-llms-txt/internal/section"
+)
+
+// runSections splits the documents of one or more JSON corpora into their
+// heading-delimited sections and writes them as JSONL, one record per
+// section with its normalized level, anchor, parent and body. Anchors are
+// unique per corpus file, matching the Markdown file it is concatenated into.
+//
+//	crawler sections [-out sections.jsonl] [corpus.json...]
+func runSections(args []string) {
+	var out string
+
+	fs := flag.NewFlagSet("sections", flag.ExitOnError)
+	fs.StringVar(&out, "out", "", "output JSONL file (default stdout)")
+	_ = fs.Parse(args)
+
+	paths := fs.Args()
+	if len(paths) == 0 {
+		var err error
+		if paths, err = corpus.DefaultPaths(); err != nil {
+			log.Fatalf("find corpora: %v", err)
+		}
+		if len(paths) == 0 {
+			log.Fatalf("no corpus files found under docs/; run the crawler first or pass corpus.json paths")
+		}
+	}
+
+	w := os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			log.Fatalf("create %s: %v", out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+
+	total := 0
+	for _, path := range paths {
+		trees := section.ParseAll(loadDocuments([]string{path}))
+		if err := section.WriteJSONL(bw, trees); err != nil {
+			log.Fatalf("write sections: %v", err)
+		}
+		for _, t := range trees {
+			total += len(t.Sections)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		log.Fatalf("flush: %v", err)
+	}
+	log.Printf("wrote %d sections", total)
+}
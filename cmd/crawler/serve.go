@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"maplestory-world-llms-txt/internal/server"
+)
+
+// runServe serves the corpus over HTTP.
+//
+//	crawler serve [-addr :8080] [corpus.json...]
+func runServe(args []string) {
+	var addr string
+
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.StringVar(&addr, "addr", "localhost:8080", "listen address")
+	_ = fs.Parse(args)
+
+	c := loadCorpus(fs.Args())
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           server.New(c),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("serving %d documents on http://%s", len(c.Docs), addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("http server: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+
+	"maplestory-world-llms-txt/internal/sqlexport"
+)
+
+// runSQLite exports a corpus into a SQLite database with documents,
+// sections, the API model and links in normalized tables and an FTS5 index
+// over the sections. See package sqlexport for the schema.
+//
+//	crawler sqlite [-out corpus.db] [corpus.json...]
+func runSQLite(args []string) {
+	var out string
+
+	fs := flag.NewFlagSet("sqlite", flag.ExitOnError)
+	fs.StringVar(&out, "out", "corpus.db", "output database file, replaced if it exists")
+	_ = fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c := loadCorpus(fs.Args())
+	stats, err := sqlexport.Create(ctx, out, c.Docs)
+	if err != nil {
+		log.Fatalf("export %s: %v", out, err)
+	}
+	log.Printf("wrote %d documents, %d sections, %d classes, %d members, %d parameters and %d links to %s (schema v%d)",
+		stats.Documents, stats.Sections, stats.Classes, stats.Members, stats.Parameters, stats.Links, out, sqlexport.SchemaVersion)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	"maplestory-world-llms-txt/internal/crawler"
+	"maplestory-world-llms-txt/internal/document"
+)
+
+// runTree exports the navigation tree of the selected targets as a JSON array
+// of trees, without fetching any document content.
+//
+//	crawler tree [-lang en] [-kind api] [-resolve] [-out tree.json]
+func runTree(args []string) {
+	var (
+		browser browserFlags
+		lang    string
+		kind    string
+		resolve bool
+		out     string
+	)
+
+	fs := flag.NewFlagSet("tree", flag.ExitOnError)
+	browser.register(fs)
+	fs.StringVar(&lang, "lang", "", "only export this language: en, ko (or kr); empty = all")
+	fs.StringVar(&kind, "kind", "", "only export this section: reference or api; empty = all")
+	fs.BoolVar(&resolve, "resolve", false, "click every leaf to resolve its URL (one page load per leaf)")
+	fs.StringVar(&out, "out", "", "output JSON file (default stdout)")
+	_ = fs.Parse(args)
+	if lang == "kr" {
+		lang = "ko"
+	}
+
+	var urls []string
+	for u := range targets {
+		if (lang == "" || document.LangOf(u) == lang) && (kind == "" || string(document.KindOf(u)) == kind) {
+			urls = append(urls, u)
+		}
+	}
+	if len(urls) == 0 {
+		log.Fatalf("no targets match -lang %q -kind %q", lang, kind)
+	}
+	sort.Strings(urls)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sess := browser.session()
+	if err := sess.Open(ctx); err != nil {
+		log.Fatalf("open browser: %v", err)
+	}
+	defer sess.Close()
+	c := crawler.NewCrawler(browser.crawlerOptions(sess)...)
+
+	trees := make([]*crawler.NavTree, 0, len(urls))
+	for _, u := range urls {
+		t, err := c.Tree(ctx, u, resolve)
+		if err != nil {
+			_ = sess.Close()
+			log.Fatalf("tree %s: %v", u, err)
+		}
+		log.Printf("navigation tree %s", t.Summary())
+		trees = append(trees, t)
+	}
+
+	data, err := json.MarshalIndent(trees, "", "  ")
+	if err != nil {
+		log.Fatalf("encode trees: %v", err)
+	}
+	data = append(data, '\n')
+	if out == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(out, data, 0o644); err != nil {
+		log.Fatalf("write %s: %v", out, err)
+	}
+	log.Printf("wrote %d trees to %s", len(trees), out)
+}
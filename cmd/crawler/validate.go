@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"maplestory-world-llms-txt/internal/corpus"
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/validate"
+)
+
+// runValidate lints crawled or converted corpora and prints one line per
+// issue. With -strict any issue makes the command exit nonzero.
+//
+//	crawler validate [-strict] [-previous 'old/docs/*/*.json'] [-json] [corpus.json...]
+func runValidate(args []string) {
+	var (
+		strict   bool
+		previous string
+		minBody  int
+		shrink   float64
+		asJSON   bool
+	)
+
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	fs.BoolVar(&strict, "strict", false, "exit nonzero when any issue is found")
+	fs.StringVar(&previous, "previous", "", "glob of corpus files from the previous crawl to compare body lengths against")
+	fs.IntVar(&minBody, "min-body", 40, "body length in characters below which a document is near-empty")
+	fs.Float64Var(&shrink, "shrink", 0.5, "report documents shorter than this fraction of their previous version (0 = off)")
+	fs.BoolVar(&asJSON, "json", false, "print issues as JSON")
+	_ = fs.Parse(args)
+
+	paths := fs.Args()
+	if len(paths) == 0 {
+		var err error
+		if paths, err = corpus.DefaultPaths(); err != nil {
+			log.Fatalf("find corpora: %v", err)
+		}
+		if len(paths) == 0 {
+			log.Fatalf("no corpus files found under docs/; run the crawler first or pass corpus.json paths")
+		}
+	}
+	docs := loadDocuments(paths)
+
+	opts := []validate.Option{
+		validate.WithMinBodyRunes(minBody),
+		validate.WithShrinkRatio(shrink),
+	}
+	if previous != "" {
+		prevPaths, err := filepath.Glob(previous)
+		if err != nil {
+			log.Fatalf("previous: %v", err)
+		}
+		opts = append(opts, validate.WithPrevious(loadDocuments(prevPaths)))
+	}
+	issues := validate.NewValidator(opts...).Check(docs)
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if issues == nil {
+			issues = []validate.Issue{}
+		}
+		if err := enc.Encode(issues); err != nil {
+			log.Fatalf("encode issues: %v", err)
+		}
+	} else {
+		for _, is := range issues {
+			fmt.Println(is)
+		}
+	}
+	log.Printf("validated %d documents: %d issues", len(docs), len(issues))
+
+	if strict && len(issues) > 0 {
+		os.Exit(1)
+	}
+}
+
+// loadDocuments reads and concatenates the given corpus files without
+// de-duplicating them, so duplicates stay visible to the validator.
+func loadDocuments(paths []string) []document.Document {
+	var docs []document.Document
+	for _, p := range paths {
+		d, err := document.LoadJSON(p)
+		if err != nil {
+			log.Fatalf("load %s: %v", p, err)
+		}
+		docs = append(docs, d...)
+	}
+	return docs
+}
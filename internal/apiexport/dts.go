@@ -0,0 +1,238 @@
+package apiexport
+
+import (
+	"fmt"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/apiref"
+)
+
+// tsReserved lists the words TypeScript rejects as parameter names.
+var tsReserved = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true,
+	"debugger": true, "default": true, "delete": true, "do": true, "else": true, "enum": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "function": true,
+	"if": true, "import": true, "in": true, "instanceof": true, "new": true, "null": true,
+	"return": true, "super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true, "with": true,
+}
+
+// DTS returns TypeScript declarations for classes: an interface per class
+// extending its base, ReadOnly properties as readonly, badges as JSDoc tags,
+// an <Name>Events map per class that emits events, and opaque declarations
+// for referenced types without a page (empty enums for enum types).
+func DTS(classes []*apiref.Class) string {
+	m := newModel(classes)
+	var b strings.Builder
+	b.WriteString("// MapleStory Worlds API declarations generated from the crawled apiReference pages.\n\n")
+
+	for _, c := range m.classes {
+		writeDoc(&b, "", c.Description, c.URL, c.Badges)
+		fmt.Fprintf(&b, "interface %s", c.Name)
+		if p := m.parent(c); p != "" {
+			fmt.Fprintf(&b, " extends %s", p)
+		}
+		b.WriteString(" {\n")
+		for _, p := range m.members(c.Properties, c) {
+			writeDoc(&b, "\t", p.Description, "", p.Badges)
+			ro := ""
+			if p.HasBadge("ReadOnly") {
+				ro = "readonly "
+			}
+			fmt.Fprintf(&b, "\t%s%s: %s;\n", ro, p.Name, m.ts(ParseType(p.Type.Name)))
+		}
+		for _, f := range m.members(c.Methods, c) {
+			writeDoc(&b, "\t", f.Description, "", f.Badges)
+			ret := "void"
+			if f.Type.Name != "" {
+				ret = m.ts(ParseType(f.Type.Name))
+			}
+			fmt.Fprintf(&b, "\t%s(%s): %s;\n", f.Name, m.tsParams(f.Params), ret)
+		}
+		b.WriteString("}\n\n")
+
+		if m.hasEvents(c) {
+			fmt.Fprintf(&b, "/** Events emitted by %s, keyed by event type. */\n", c.Name)
+			fmt.Fprintf(&b, "interface %sEvents", c.Name)
+			if p := m.parent(c); p != "" && m.hasEvents(m.byName[p]) {
+				fmt.Fprintf(&b, " extends %sEvents", p)
+			}
+			b.WriteString(" {\n")
+			for _, e := range m.members(c.Events, c) {
+				writeDoc(&b, "\t", e.Description, "", e.Badges)
+				fmt.Fprintf(&b, "\t%s: %s;\n", e.Name, m.ts(ParseType(e.Type.Name)))
+			}
+			b.WriteString("}\n\n")
+		}
+	}
+
+	for _, name := range m.opaque() {
+		if !isIdent(name) {
+			continue
+		}
+		if url := m.refs[name]; url != "" {
+			fmt.Fprintf(&b, "/** @see %s */\n", url)
+		}
+		if m.isEnum(name) {
+			// Enum values are listed by the type index only.
+			fmt.Fprintf(&b, "declare enum %s {}\n\n", name)
+		} else {
+			fmt.Fprintf(&b, "interface %s {}\n\n", name)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// hasEvents reports whether c or a base class in the export emits events.
+func (m *model) hasEvents(c *apiref.Class) bool {
+	if len(c.Events) > 0 {
+		return true
+	}
+	p := m.parent(c)
+	return p != "" && m.hasEvents(m.byName[p])
+}
+
+// ts returns the TypeScript form of a type expression.
+func (m *model) ts(t Type) string {
+	switch {
+	case t.Tuple != nil:
+		parts := make([]string, len(t.Tuple))
+		for i, e := range t.Tuple {
+			parts[i] = m.ts(e)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case t.Func():
+		params := make([]string, len(t.Args))
+		for i, a := range t.Args {
+			params[i] = fmt.Sprintf("arg%d: %s", i+1, m.ts(a))
+		}
+		ret := "void"
+		if t.Return != nil {
+			ret = m.ts(*t.Return)
+		}
+		if t.Args == nil && t.Return == nil {
+			params = []string{"...args: any[]"}
+			ret = "any"
+		}
+		return "(" + strings.Join(params, ", ") + ") => " + ret
+	case listTypes[t.Name] && len(t.Args) == 1:
+		elem := m.ts(t.Args[0])
+		if strings.ContainsAny(elem, " |") {
+			elem = "(" + elem + ")"
+		}
+		if t.Name == "ReadOnlyList" {
+			return "readonly " + elem + "[]"
+		}
+		return elem + "[]"
+	case (dictTypes[t.Name] || t.Name == "table") && len(t.Args) == 2:
+		kind := "Map"
+		if t.Name == "ReadOnlyDictionary" {
+			kind = "ReadonlyMap"
+		}
+		return fmt.Sprintf("%s<%s, %s>", kind, m.ts(t.Args[0]), m.ts(t.Args[1]))
+	case t.Args != nil:
+		// An unknown generic
+		return "any"
+	}
+	switch scalars[t.Name] {
+	case kindNumber, kindInteger:
+		return "number"
+	case kindString:
+		return "string"
+	case kindBoolean:
+		return "boolean"
+	case kindVoid:
+		return "void"
+	case kindAny:
+		return "any"
+	}
+	if !isIdent(t.Name) {
+		return "any"
+	}
+	return t.Name
+}
+
+// tsParams returns a method's parameter list. Parameters with a default are
+// optional unless a required parameter follows them.
+func (m *model) tsParams(params []apiref.Param) string {
+	lastRequired := -1
+	for i, p := range params {
+		if p.Default == "" {
+			lastRequired = i
+		}
+	}
+	parts := make([]string, len(params))
+	for i, p := range params {
+		name := p.Name
+		if !isIdent(name) {
+			name = fmt.Sprintf("arg%d", i+1)
+		} else if tsReserved[name] {
+			name += "_"
+		}
+		opt := ""
+		if i > lastRequired {
+			opt = "?"
+		}
+		parts[i] = fmt.Sprintf("%s%s: %s", name, opt, m.ts(ParseType(p.Type.Name)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeDoc writes a JSDoc comment with the description, a @see link and one
+// tag per badge ("HideFromInspector" -> @hideFromInspector).
+func writeDoc(b *strings.Builder, indent, desc, url string, badges []string) {
+	var lines []string
+	if desc = strings.TrimSpace(strings.ReplaceAll(desc, `\|`, "|")); desc != "" {
+		lines = append(lines, strings.Split(desc, "\n")...)
+	}
+	for _, badge := range badges {
+		if tag := badgeTag(badge); tag != "" {
+			lines = append(lines, "@"+tag)
+		}
+	}
+	if url != "" {
+		lines = append(lines, "@see "+url)
+	}
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s/**\n", indent)
+	for _, l := range lines {
+		fmt.Fprintf(b, "%s * %s\n", indent, strings.ReplaceAll(l, "*/", "*\\/"))
+	}
+	fmt.Fprintf(b, "%s */\n", indent)
+}
+
+// badgeTag turns a badge into a JSDoc tag name: "ReadOnly" -> "readonly",
+// "server only" -> "serverOnly".
+func badgeTag(badge string) string {
+	if strings.EqualFold(badge, "ReadOnly") {
+		return "readonly"
+	}
+	var b strings.Builder
+	for i, w := range strings.FieldsFunc(badge, func(r rune) bool { return !isIdentRune(r) }) {
+		if i == 0 {
+			w = strings.ToLower(w[:1]) + w[1:]
+		} else {
+			w = strings.ToUpper(w[:1]) + w[1:]
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+func isIdent(s string) bool {
+	if s == "" || s[0] >= '0' && s[0] <= '9' {
+		return false
+	}
+	for _, r := range s {
+		if !isIdentRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
@@ -0,0 +1,55 @@
+package apiexport
+
+import (
+	"strings"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/apiref"
+)
+
+func TestDTS(t *testing.T) {
+	out := DTS(sampleClasses())
+	for _, want := range []string{
+		"/**\n * A child.\n * @see https://example.com/apiReference/Components/Child\n */\ninterface Child extends Component {\n",
+		"\t/**\n\t * Range.\n\t */\n\tRange: number;\n",
+		"\t/**\n\t * @readonly\n\t * @sync\n\t */\n\treadonly Target: EntityRef;\n",
+		"\tCreateNode(nodeType: string, fn?: (arg1: number) => BehaviourTreeStatus): BTNode;\n",
+		"\tGetPoints(): [Vector2, Vector2];\n",
+		"interface ChildEvents {\n\t/**\n\t * Occurs when hit.\n\t */\n\tHitEvent: HitEvent;\n}",
+		"interface Component {\n",
+		"/** @see https://example.com/apiReference/Enums/UpdateAuthorityType */\ndeclare enum UpdateAuthorityType {}\n",
+		"/** @see https://example.com/apiReference/Misc/EntityRef */\ninterface EntityRef {}\n",
+		"interface BehaviourTreeStatus {}\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	// Inherited members live on the base class only
+	if strings.Count(out, "Enable: boolean;") != 1 {
+		t.Fatalf("inherited member should be declared once:\n%s", out)
+	}
+}
+
+func TestDTS_MissingBase(t *testing.T) {
+	out := DTS(sampleClasses()[:1])
+	if !strings.Contains(out, "interface Child {\n") || !strings.Contains(out, "\tEnable: boolean;\n") {
+		t.Fatalf("without a base page the inherited members should stay on the class:\n%s", out)
+	}
+	if !strings.Contains(out, "interface Component {}") {
+		t.Fatalf("the missing base should be declared opaquely:\n%s", out)
+	}
+}
+
+func TestTSParams(t *testing.T) {
+	m := newModel(nil)
+	got := m.tsParams([]apiref.Param{
+		{Name: "default", Type: apiref.TypeRef{Name: "int32"}, Default: "0"},
+		{Name: "items", Type: apiref.TypeRef{Name: "table<string>"}},
+		{Name: "map", Type: apiref.TypeRef{Name: "ReadOnlyDictionary<string, number>"}, Default: "nil"},
+	})
+	want := "default_: number, items: string[], map?: ReadonlyMap<string, number>"
+	if got != want {
+		t.Fatalf("tsParams = %q, want %q", got, want)
+	}
+}
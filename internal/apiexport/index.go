@@ -0,0 +1,256 @@
+package apiexport
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/apiref"
+)
+
+// TypeKind classifies an entry of the type index.
+type TypeKind string
+
+const (
+	// KindClass is a class with a page in the corpus.
+	KindClass TypeKind = "class"
+	// KindEnum is a type linked to an enum page.
+	KindEnum TypeKind = "enum"
+	// KindEvent is a type linked to an event page.
+	KindEvent TypeKind = "event"
+	// KindType is any other referenced type (Misc classes and the like).
+	KindType TypeKind = "type"
+)
+
+// Usage roles: how a member refers to a type.
+const (
+	RoleProperty  = "property"
+	RoleParameter = "parameter"
+	RoleReturn    = "return"
+	RoleEvent     = "event"
+)
+
+// MemberRef names a member declared on a class.
+type MemberRef struct {
+	Kind      apiref.MemberKind `json:"kind"`
+	Name      string            `json:"name"`
+	Signature string            `json:"signature"`
+}
+
+// Usage is a member that refers to a type.
+type Usage struct {
+	Class  string `json:"class"`
+	Member string `json:"member"`
+	// Role is property, parameter, return or event.
+	Role string `json:"role"`
+	// Param names the parameter for the parameter role.
+	Param     string `json:"param,omitempty"`
+	Signature string `json:"signature"`
+}
+
+// TypeEntry is everything the index knows about one type.
+type TypeEntry struct {
+	Name string   `json:"name"`
+	Kind TypeKind `json:"kind"`
+	URL  string   `json:"url,omitempty"`
+	// Bases and Subclasses are the nearest-first base classes and the
+	// classes deriving directly from this one.
+	Bases      []string `json:"bases,omitempty"`
+	Subclasses []string `json:"subclasses,omitempty"`
+	// Members are the members the class declares itself.
+	Members []MemberRef `json:"members,omitempty"`
+	// Values are the members of an enum, as listed on its page.
+	Values []apiref.EnumValue `json:"values,omitempty"`
+	Usages []Usage            `json:"usages,omitempty"`
+}
+
+// TypeIndex cross-references the types of the API model.
+type TypeIndex struct {
+	Types  []TypeEntry `json:"types"`
+	byName map[string]int
+}
+
+// BuildIndex maps every class and every named type the classes refer to onto
+// its members, its usages (as property type, parameter or return, including
+// inside generics such as SyncList<Vector2>) and its subclasses. Enums come
+// from their own pages with their values, whether or not a class refers to
+// them. Primitive types are left out; inherited members count as usages of
+// the base class only.
+func BuildIndex(classes []*apiref.Class, enums []*apiref.Enum) *TypeIndex {
+	m := newModel(classes)
+	ix := &TypeIndex{byName: make(map[string]int)}
+	entry := func(name string) *TypeEntry {
+		if i, ok := ix.byName[name]; ok {
+			return &ix.Types[i]
+		}
+		ix.byName[name] = len(ix.Types)
+		ix.Types = append(ix.Types, TypeEntry{Name: name})
+		return &ix.Types[len(ix.Types)-1]
+	}
+
+	for _, c := range m.classes {
+		e := entry(c.Name)
+		e.Kind, e.URL, e.Bases = KindClass, c.URL, c.Bases
+		for _, mem := range apiref.Own(c.Members()) {
+			e.Members = append(e.Members, MemberRef{Kind: mem.Kind, Name: mem.Name, Signature: mem.Signature})
+		}
+	}
+	for _, en := range enums {
+		e := entry(en.Name)
+		e.Kind, e.URL, e.Values = KindEnum, en.URL, en.Values
+	}
+	for _, name := range m.opaque() {
+		if _, ok := ix.byName[name]; ok || !isIdent(name) {
+			continue
+		}
+		e := entry(name)
+		e.URL = m.refs[name]
+		switch {
+		case m.isEnum(name):
+			e.Kind = KindEnum
+		case strings.Contains(e.URL, "/apiReference/Events/"):
+			e.Kind = KindEvent
+		default:
+			e.Kind = KindType
+		}
+	}
+
+	for _, c := range m.classes {
+		if len(c.Bases) > 0 {
+			base := entry(c.Bases[0])
+			base.Subclasses = append(base.Subclasses, c.Name)
+		}
+		for _, mem := range apiref.Own(c.Members()) {
+			use := func(typ, role, param string) {
+				for _, name := range typeNames(ParseType(typ)) {
+					if i, ok := ix.byName[name]; ok {
+						ix.Types[i].Usages = append(ix.Types[i].Usages, Usage{
+							Class: c.Name, Member: mem.Name, Role: role, Param: param, Signature: mem.Signature,
+						})
+					}
+				}
+			}
+			switch mem.Kind {
+			case apiref.Property:
+				use(mem.Type.Name, RoleProperty, "")
+			case apiref.Event:
+				use(mem.Type.Name, RoleEvent, "")
+			case apiref.Method:
+				use(mem.Type.Name, RoleReturn, "")
+				for _, p := range mem.Params {
+					use(p.Type.Name, RoleParameter, p.Name)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(ix.Types, func(i, j int) bool { return ix.Types[i].Name < ix.Types[j].Name })
+	for i := range ix.Types {
+		ix.byName[ix.Types[i].Name] = i
+		sort.Strings(ix.Types[i].Subclasses)
+	}
+	return ix
+}
+
+// Type returns the entry of a type.
+func (ix *TypeIndex) Type(name string) (TypeEntry, bool) {
+	i, ok := ix.byName[name]
+	if !ok {
+		return TypeEntry{}, false
+	}
+	return ix.Types[i], true
+}
+
+// typeNames returns the named, non-primitive types in t, deduplicated.
+func typeNames(t Type) []string {
+	var (
+		out  []string
+		seen = make(map[string]bool)
+		walk func(Type)
+	)
+	walk = func(t Type) {
+		for _, e := range t.Tuple {
+			walk(e)
+		}
+		for _, a := range t.Args {
+			walk(a)
+		}
+		if t.Return != nil {
+			walk(*t.Return)
+		}
+		if t.Name == "" || t.Func() || t.Args != nil || scalars[t.Name] != "" || seen[t.Name] {
+			return
+		}
+		seen[t.Name] = true
+		out = append(out, t.Name)
+	}
+	walk(t)
+	return out
+}
+
+// Markdown renders the index as a "Type index" page: one section per type
+// with its kind, reference link, inheritance, members and usages.
+func (ix *TypeIndex) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Type index\n\n")
+	b.WriteString("Every type referenced by the API reference, with the members that take or return it and the classes deriving from it.\n\n")
+	for _, e := range ix.Types {
+		fmt.Fprintf(&b, "## %s\n\n", e.Name)
+		kind := string(e.Kind)
+		if e.URL != "" {
+			kind = fmt.Sprintf("%s, [reference](%s)", kind, e.URL)
+		}
+		fmt.Fprintf(&b, "Kind: %s\n\n", kind)
+		if len(e.Bases) > 0 {
+			fmt.Fprintf(&b, "Inherits: %s\n\n", strings.Join(e.Bases, " → "))
+		}
+		if len(e.Subclasses) > 0 {
+			fmt.Fprintf(&b, "Subclasses: %s\n\n", strings.Join(e.Subclasses, ", "))
+		}
+		if len(e.Values) > 0 {
+			values := make([]string, len(e.Values))
+			for i, v := range e.Values {
+				values[i] = v.Name
+				if v.Value != "" {
+					values[i] += " = " + v.Value
+				}
+			}
+			fmt.Fprintf(&b, "Values: %s\n\n", strings.Join(values, ", "))
+		}
+		for _, kind := range []apiref.MemberKind{apiref.Property, apiref.Method, apiref.Event} {
+			var names []string
+			for _, mem := range e.Members {
+				// Overloads are listed once
+				if mem.Kind == kind && (len(names) == 0 || names[len(names)-1] != mem.Name) {
+					names = append(names, mem.Name)
+				}
+			}
+			if len(names) > 0 {
+				fmt.Fprintf(&b, "%s: %s\n\n", memberHeading(kind), strings.Join(names, ", "))
+			}
+		}
+		if len(e.Usages) > 0 {
+			b.WriteString("Used by:\n\n")
+			for _, u := range e.Usages {
+				role := u.Role
+				if u.Param != "" {
+					role += " " + u.Param
+				}
+				fmt.Fprintf(&b, "- `%s.%s` (%s)\n", u.Class, u.Member, role)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func memberHeading(k apiref.MemberKind) string {
+	switch k {
+	case apiref.Property:
+		return "Properties"
+	case apiref.Method:
+		return "Methods"
+	default:
+		return "Events"
+	}
+}
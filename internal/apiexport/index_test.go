@@ -0,0 +1,92 @@
+package apiexport
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/apiref"
+)
+
+func TestBuildIndex(t *testing.T) {
+	ix := BuildIndex(sampleClasses(), nil)
+
+	comp, ok := ix.Type("Component")
+	if !ok || comp.Kind != KindClass || !reflect.DeepEqual(comp.Subclasses, []string{"Child"}) {
+		t.Fatalf("Component = %+v", comp)
+	}
+	if len(comp.Members) != 2 || comp.Members[0].Name != "Enable" {
+		t.Fatalf("Component members = %+v", comp.Members)
+	}
+
+	child, _ := ix.Type("Child")
+	if len(child.Members) != 5 {
+		t.Fatalf("Child should list only its own members: %+v", child.Members)
+	}
+
+	enum, _ := ix.Type("UpdateAuthorityType")
+	want := []Usage{{Class: "Component", Member: "UpdateAuthority", Role: RoleProperty}}
+	if enum.Kind != KindEnum || !reflect.DeepEqual(enum.Usages, want) {
+		t.Fatalf("UpdateAuthorityType = %+v", enum)
+	}
+
+	status, _ := ix.Type("BehaviourTreeStatus")
+	if len(status.Usages) != 1 || status.Usages[0].Role != RoleParameter || status.Usages[0].Param != "fn" {
+		t.Fatalf("callback return should count as a parameter usage: %+v", status.Usages)
+	}
+	vec, _ := ix.Type("Vector2")
+	if len(vec.Usages) != 1 || vec.Usages[0].Role != RoleReturn {
+		t.Fatalf("tuple returns should be a single usage: %+v", vec.Usages)
+	}
+	if _, ok := ix.Type("float"); ok {
+		t.Fatalf("primitives should not be indexed")
+	}
+
+	for i := 1; i < len(ix.Types); i++ {
+		if ix.Types[i-1].Name > ix.Types[i].Name {
+			t.Fatalf("types not sorted: %s > %s", ix.Types[i-1].Name, ix.Types[i].Name)
+		}
+	}
+}
+
+func TestTypeIndexMarkdown(t *testing.T) {
+	out := BuildIndex(sampleClasses(), nil).Markdown()
+	for _, want := range []string{
+		"# Type index\n\n",
+		"## Component\n\nKind: class\n\nSubclasses: Child\n\nProperties: Enable, UpdateAuthority\n\n",
+		"## Child\n\nKind: class, [reference](https://example.com/apiReference/Components/Child)\n\nInherits: Component\n\n",
+		"## EntityRef\n\nKind: type, [reference](https://example.com/apiReference/Misc/EntityRef)\n\nUsed by:\n\n- `Child.Target` (property)\n",
+		"- `Child.CreateNode` (parameter fn)\n",
+		"## HitEvent\n\nKind: type\n\nUsed by:\n\n- `Child.HitEvent` (event)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestBuildIndex_EnumValues(t *testing.T) {
+	page := "# UpdateAuthorityType\n\nWhere a component is updated.\n\n# Members\n\n" +
+		"| Server = 0 |\n| --- |\n| Updated on the server. |\n\n| Client = 1 |\n| --- |\n| Updated on the client. |\n"
+	updateAuthority, ok := apiref.ParseEnum("https://example.com/apiReference/Enums/UpdateAuthorityType", page)
+	if !ok {
+		t.Fatal("ParseEnum reported no enum")
+	}
+	unused, _ := apiref.ParseEnum("https://example.com/apiReference/Enums/Unused", "# Unused\n\n# Members\n\n| None |\n| --- |\n| Nothing. |\n")
+	ix := BuildIndex(sampleClasses(), []*apiref.Enum{updateAuthority, unused})
+
+	enum, _ := ix.Type("UpdateAuthorityType")
+	want := []apiref.EnumValue{
+		{Name: "Server", Value: "0", Description: "Updated on the server."},
+		{Name: "Client", Value: "1", Description: "Updated on the client."},
+	}
+	if enum.Kind != KindEnum || !reflect.DeepEqual(enum.Values, want) || len(enum.Usages) != 1 {
+		t.Fatalf("UpdateAuthorityType = %+v", enum)
+	}
+	if e, ok := ix.Type("Unused"); !ok || e.Kind != KindEnum || len(e.Values) != 1 {
+		t.Fatalf("unreferenced enums should be indexed: %+v", e)
+	}
+	if out := ix.Markdown(); !strings.Contains(out, "Values: Server = 0, Client = 1\n\n") {
+		t.Fatalf("expected values in Markdown, got:\n%s", out)
+	}
+}
@@ -0,0 +1,165 @@
+package apiexport
+
+import (
+	"encoding/json"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/apiref"
+)
+
+// SchemaDialect is the JSON Schema draft the export conforms to.
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema returns a JSON Schema whose $defs describe every class as an object
+// schema: properties with their types, readOnly for ReadOnly members and
+// x-sync / x-badges for the other badges; allOf references the base class.
+// Methods and events are not data, so they are described under the
+// x-methods and x-events extension keywords, as lists so overloads survive.
+// Referenced types without a page get an opaque definition; enums are marked
+// with x-enum.
+func Schema(classes []*apiref.Class) map[string]any {
+	m := newModel(classes)
+	defs := make(map[string]any)
+
+	for _, c := range m.classes {
+		def := map[string]any{"type": "object", "title": c.Name}
+		setDoc(def, c.Description, c.URL, c.Badges)
+		if p := m.parent(c); p != "" {
+			def["allOf"] = []any{ref(p)}
+		}
+
+		props := make(map[string]any)
+		for _, p := range m.members(c.Properties, c) {
+			s := m.schema(ParseType(p.Type.Name))
+			setDoc(s, p.Description, "", p.Badges)
+			if p.HasBadge("ReadOnly") {
+				s["readOnly"] = true
+			}
+			if p.HasBadge("Sync") {
+				s["x-sync"] = true
+			}
+			props[p.Name] = s
+		}
+		if len(props) > 0 {
+			def["properties"] = props
+		}
+
+		var methods []any
+		for _, f := range m.members(c.Methods, c) {
+			params := make([]any, len(f.Params))
+			for i, p := range f.Params {
+				ps := map[string]any{"name": p.Name, "schema": m.schema(ParseType(p.Type.Name))}
+				if p.Default != "" {
+					ps["default"] = p.Default
+				}
+				params[i] = ps
+			}
+			method := map[string]any{"name": f.Name, "parameters": params, "signature": f.Signature}
+			if f.Type.Name != "" && scalars[f.Type.Name] != kindVoid {
+				method["returns"] = m.schema(ParseType(f.Type.Name))
+			}
+			setDoc(method, f.Description, "", f.Badges)
+			methods = append(methods, method)
+		}
+		if len(methods) > 0 {
+			def["x-methods"] = methods
+		}
+
+		var events []any
+		for _, e := range m.members(c.Events, c) {
+			ev := ref(e.Name)
+			setDoc(ev, e.Description, "", e.Badges)
+			events = append(events, ev)
+		}
+		if len(events) > 0 {
+			def["x-events"] = events
+		}
+		defs[c.Name] = def
+	}
+
+	for _, name := range m.opaque() {
+		def := map[string]any{"title": name}
+		if url := m.refs[name]; url != "" {
+			def["x-url"] = url
+		}
+		if m.isEnum(name) {
+			// World data is not checked against enum values
+			def["x-enum"] = true
+		} else {
+			def["type"] = "object"
+		}
+		defs[name] = def
+	}
+
+	return map[string]any{
+		"$schema":     SchemaDialect,
+		"title":       "MapleStory Worlds API",
+		"description": "Generated from the crawled apiReference pages.",
+		"$defs":       defs,
+	}
+}
+
+// MarshalSchema returns the schema as indented JSON. Keys are sorted, so the
+// output is stable across runs.
+func MarshalSchema(classes []*apiref.Class) ([]byte, error) {
+	b, err := json.MarshalIndent(Schema(classes), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// schema returns the JSON Schema of a type expression.
+func (m *model) schema(t Type) map[string]any {
+	switch {
+	case t.Tuple != nil:
+		items := make([]any, len(t.Tuple))
+		for i, e := range t.Tuple {
+			items[i] = m.schema(e)
+		}
+		return map[string]any{"type": "array", "prefixItems": items, "items": false}
+	case t.Func():
+		return map[string]any{"x-function": t.String()}
+	case listTypes[t.Name] && len(t.Args) == 1:
+		return map[string]any{"type": "array", "items": m.schema(t.Args[0])}
+	case (dictTypes[t.Name] || t.Name == "table") && len(t.Args) == 2:
+		return map[string]any{"type": "object", "additionalProperties": m.schema(t.Args[1])}
+	case t.Args != nil:
+		return map[string]any{}
+	}
+	switch scalars[t.Name] {
+	case kindNumber:
+		return map[string]any{"type": "number"}
+	case kindInteger:
+		return map[string]any{"type": "integer"}
+	case kindString:
+		return map[string]any{"type": "string"}
+	case kindBoolean:
+		return map[string]any{"type": "boolean"}
+	case kindVoid:
+		return map[string]any{"type": "null"}
+	case kindAny:
+		return map[string]any{}
+	}
+	if !isIdent(t.Name) {
+		return map[string]any{}
+	}
+	return ref(t.Name)
+}
+
+func ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+// setDoc adds the description, reference URL and badges to a schema.
+func setDoc(s map[string]any, desc, url string, badges []string) {
+	if desc = strings.TrimSpace(strings.ReplaceAll(desc, `\|`, "|")); desc != "" {
+		s["description"] = desc
+	}
+	if url != "" {
+		s["x-url"] = url
+	}
+	if len(badges) > 0 {
+		s["x-badges"] = badges
+	}
+}
@@ -0,0 +1,62 @@
+package apiexport
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSchema(t *testing.T) {
+	data, err := MarshalSchema(sampleClasses())
+	if err != nil {
+		t.Fatalf("MarshalSchema: %v", err)
+	}
+	var doc struct {
+		Schema string                    `json:"$schema"`
+		Defs   map[string]map[string]any `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if doc.Schema != SchemaDialect {
+		t.Fatalf("$schema = %q", doc.Schema)
+	}
+
+	child := doc.Defs["Child"]
+	if !reflect.DeepEqual(child["allOf"], []any{map[string]any{"$ref": "#/$defs/Component"}}) {
+		t.Fatalf("allOf = %v", child["allOf"])
+	}
+	props := child["properties"].(map[string]any)
+	if _, ok := props["Enable"]; ok {
+		t.Fatalf("inherited property should only be on the base: %v", props)
+	}
+	want := map[string]any{"$ref": "#/$defs/EntityRef", "readOnly": true, "x-sync": true, "x-badges": []any{"ReadOnly", "Sync"}}
+	if !reflect.DeepEqual(props["Target"], want) {
+		t.Fatalf("Target = %v", props["Target"])
+	}
+	if !reflect.DeepEqual(props["Range"], map[string]any{"type": "number", "description": "Range."}) {
+		t.Fatalf("Range = %v", props["Range"])
+	}
+
+	methods := child["x-methods"].([]any)
+	create := methods[0].(map[string]any)
+	params := create["parameters"].([]any)
+	if create["name"] != "CreateNode" || len(params) != 2 ||
+		!reflect.DeepEqual(params[1], map[string]any{"name": "fn", "default": "nil", "schema": map[string]any{"x-function": "func<float> -> BehaviourTreeStatus"}}) {
+		t.Fatalf("CreateNode = %v", create)
+	}
+	points := methods[1].(map[string]any)["returns"].(map[string]any)
+	if points["type"] != "array" || len(points["prefixItems"].([]any)) != 2 {
+		t.Fatalf("GetPoints returns = %v", points)
+	}
+	if ev := child["x-events"].([]any)[0].(map[string]any); ev["$ref"] != "#/$defs/HitEvent" {
+		t.Fatalf("x-events = %v", child["x-events"])
+	}
+
+	if e := doc.Defs["UpdateAuthorityType"]; e["x-enum"] != true || e["type"] != nil {
+		t.Fatalf("enum def = %v", e)
+	}
+	if e := doc.Defs["EntityRef"]; e["type"] != "object" || e["x-url"] != "https://example.com/apiReference/Misc/EntityRef" {
+		t.Fatalf("opaque def = %v", e)
+	}
+}
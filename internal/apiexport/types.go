@@ -0,0 +1,246 @@
+// Package apiexport writes the API model parsed from the apiReference pages
+// in formats other tools consume: TypeScript declarations (.d.ts) for editor
+// tooling and a JSON Schema for validating world data.
+//
+// Only the class pages the crawler fetched are modelled. Types that are
+// referenced but have no class page (Misc classes, events, enums) are
+// declared opaquely; enum values appear in the type index only.
+package apiexport
+
+import (
+	"sort"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/apiref"
+)
+
+// Type is a parsed type expression as written on the reference pages, such
+// as "number", "SyncList<Vector2>", "func<float> -> BehaviourTreeStatus" or
+// the multiple returns "Vector2, Vector2".
+type Type struct {
+	// Name is the type or generic name; "" for a tuple of returns.
+	Name string
+	// Args are the generic arguments, or a function's parameter types.
+	Args []Type
+	// Return is a function's result, or nil.
+	Return *Type
+	// Tuple holds multiple return values.
+	Tuple []Type
+}
+
+// Func reports whether t is a callback type.
+func (t Type) Func() bool { return t.Name == "func" || t.Name == "function" }
+
+// String writes the type expression back in the reference's notation.
+func (t Type) String() string {
+	if t.Tuple != nil {
+		parts := make([]string, len(t.Tuple))
+		for i, e := range t.Tuple {
+			parts[i] = e.String()
+		}
+		return strings.Join(parts, ", ")
+	}
+	s := t.Name
+	if len(t.Args) > 0 {
+		args := make([]string, len(t.Args))
+		for i, a := range t.Args {
+			args[i] = a.String()
+		}
+		s += "<" + strings.Join(args, ", ") + ">"
+	}
+	if t.Return != nil {
+		s += " -> " + t.Return.String()
+	}
+	return s
+}
+
+// ParseType parses a type expression. It never fails: anything it does not
+// understand is kept as a plain name.
+func ParseType(s string) Type {
+	s = strings.TrimSpace(s)
+	if parts := splitTop(s, ','); len(parts) > 1 {
+		t := Type{}
+		for _, p := range parts {
+			t.Tuple = append(t.Tuple, ParseType(p))
+		}
+		return t
+	}
+	var t Type
+	if i := topIndex(s, "->"); i >= 0 {
+		ret := ParseType(s[i+2:])
+		t.Return = &ret
+		s = strings.TrimSpace(s[:i])
+	}
+	if open := strings.IndexByte(s, '<'); open > 0 && strings.HasSuffix(s, ">") {
+		t.Name = strings.TrimSpace(s[:open])
+		for _, a := range splitTop(s[open+1:len(s)-1], ',') {
+			t.Args = append(t.Args, ParseType(a))
+		}
+		return t
+	}
+	t.Name = s
+	return t
+}
+
+// splitTop splits s on sep outside of angle brackets.
+func splitTop(s string, sep byte) []string {
+	var (
+		out   []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '<':
+			depth++
+		case '>':
+			if i > 0 && s[i-1] == '-' {
+				continue
+			}
+			depth--
+		case sep:
+			if depth == 0 {
+				out = append(out, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(out, strings.TrimSpace(s[start:]))
+}
+
+// topIndex returns the index of the first sub outside of angle brackets.
+func topIndex(s, sub string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], sub) && depth == 0 {
+			return i
+		}
+		switch s[i] {
+		case '<':
+			depth++
+		case '>':
+			if i > 0 && s[i-1] == '-' {
+				continue
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// Scalar kinds of the Lua and engine primitives.
+const (
+	kindNumber  = "number"
+	kindInteger = "integer"
+	kindString  = "string"
+	kindBoolean = "boolean"
+	kindVoid    = "void"
+	kindAny     = "any"
+)
+
+// scalars maps primitive type names to their kind.
+var scalars = map[string]string{
+	"number":  kindNumber,
+	"float":   kindNumber,
+	"double":  kindNumber,
+	"integer": kindInteger,
+	"int":     kindInteger,
+	"int32":   kindInteger,
+	"int64":   kindInteger,
+	"string":  kindString,
+	"boolean": kindBoolean,
+	"bool":    kindBoolean,
+	"void":    kindVoid,
+	"nil":     kindVoid,
+	"any":     kindAny,
+	"object":  kindAny,
+	"table":   kindAny,
+}
+
+// Generic containers: lists map to arrays, dictionaries to maps.
+var (
+	listTypes = map[string]bool{"table": true, "List": true, "SyncList": true, "ReadOnlyList": true}
+	dictTypes = map[string]bool{"Dictionary": true, "SyncDictionary": true, "ReadOnlyDictionary": true}
+)
+
+// model indexes the classes being exported and every named type they refer to.
+type model struct {
+	classes []*apiref.Class
+	byName  map[string]*apiref.Class
+	// refs maps every referenced named type to its reference URL ("" when
+	// the page does not link it).
+	refs map[string]string
+}
+
+func newModel(classes []*apiref.Class) *model {
+	m := &model{byName: make(map[string]*apiref.Class), refs: make(map[string]string)}
+	m.classes = append(m.classes, classes...)
+	sort.Slice(m.classes, func(i, j int) bool { return m.classes[i].Name < m.classes[j].Name })
+	for _, c := range m.classes {
+		m.byName[c.Name] = c
+	}
+	for _, c := range m.classes {
+		for _, b := range c.Bases {
+			m.addRef(Type{Name: b}, "")
+		}
+		for _, mem := range c.Members() {
+			m.addRef(ParseType(mem.Type.Name), mem.Type.URL)
+			for _, p := range mem.Params {
+				m.addRef(ParseType(p.Type.Name), p.Type.URL)
+			}
+		}
+	}
+	return m
+}
+
+// addRef records the named types in t. url belongs to t itself.
+func (m *model) addRef(t Type, url string) {
+	for _, e := range t.Tuple {
+		m.addRef(e, "")
+	}
+	for _, a := range t.Args {
+		m.addRef(a, "")
+	}
+	if t.Return != nil {
+		m.addRef(*t.Return, "")
+	}
+	if t.Name == "" || t.Func() || t.Args != nil || scalars[t.Name] != "" || m.byName[t.Name] != nil {
+		return
+	}
+	if m.refs[t.Name] == "" {
+		m.refs[t.Name] = url
+	}
+}
+
+// opaque returns the referenced types without a page, sorted by name.
+func (m *model) opaque() []string {
+	out := make([]string, 0, len(m.refs))
+	for name := range m.refs {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// isEnum reports whether a referenced type links to an enum page.
+func (m *model) isEnum(name string) bool {
+	return strings.Contains(m.refs[name], "/apiReference/Enums/")
+}
+
+// parent returns the base class a class extends in the export, or "" when
+// it has none; inherited members are then written on the class itself.
+func (m *model) parent(c *apiref.Class) string {
+	if len(c.Bases) > 0 && m.byName[c.Bases[0]] != nil {
+		return c.Bases[0]
+	}
+	return ""
+}
+
+// members returns the members written on c: its own, plus the inherited ones
+// when the export has no base class to carry them.
+func (m *model) members(list []apiref.Member, c *apiref.Class) []apiref.Member {
+	if m.parent(c) == "" {
+		return list
+	}
+	return apiref.Own(list)
+}
@@ -0,0 +1,64 @@
+package apiexport
+
+import (
+	"reflect"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/apiref"
+)
+
+func TestParseType(t *testing.T) {
+	ret := Type{Name: "BehaviourTreeStatus"}
+	cases := map[string]Type{
+		"number":            {Name: "number"},
+		"SyncList<Vector2>": {Name: "SyncList", Args: []Type{{Name: "Vector2"}}},
+		"ReadOnlyDictionary<A, ReadOnlyList<B>>": {Name: "ReadOnlyDictionary", Args: []Type{
+			{Name: "A"}, {Name: "ReadOnlyList", Args: []Type{{Name: "B"}}},
+		}},
+		"func<float> -> BehaviourTreeStatus": {Name: "func", Args: []Type{{Name: "float"}}, Return: &ret},
+		"Vector2, Vector2":                   {Tuple: []Type{{Name: "Vector2"}, {Name: "Vector2"}}},
+	}
+	for in, want := range cases {
+		got := ParseType(in)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseType(%q) = %+v, want %+v", in, got, want)
+		}
+		if got.String() != in {
+			t.Errorf("ParseType(%q).String() = %q", in, got.String())
+		}
+	}
+}
+
+// sampleClasses is a component inheriting from a base class, with a linked
+// enum, a callback parameter and an event.
+func sampleClasses() []*apiref.Class {
+	enumURL := "https://example.com/apiReference/Enums/UpdateAuthorityType"
+	return []*apiref.Class{
+		{
+			Name: "Child", URL: "https://example.com/apiReference/Components/Child", Description: "A child.",
+			Bases: []string{"Component"},
+			Properties: []apiref.Member{
+				{Kind: apiref.Property, Name: "Range", Type: apiref.TypeRef{Name: "float"}, Description: "Range."},
+				{Kind: apiref.Property, Name: "Target", Type: apiref.TypeRef{Name: "EntityRef", URL: "https://example.com/apiReference/Misc/EntityRef"}, Badges: []string{"ReadOnly", "Sync"}},
+				{Kind: apiref.Property, Name: "Enable", Type: apiref.TypeRef{Name: "boolean"}, InheritedFrom: "Component"},
+			},
+			Methods: []apiref.Member{
+				{Kind: apiref.Method, Name: "CreateNode", Type: apiref.TypeRef{Name: "BTNode"}, Signature: "BTNode CreateNode(string nodeType, func<float> -> BehaviourTreeStatus fn = nil)", Params: []apiref.Param{
+					{Name: "nodeType", Type: apiref.TypeRef{Name: "string"}},
+					{Name: "fn", Type: apiref.TypeRef{Name: "func<float> -> BehaviourTreeStatus"}, Default: "nil"},
+				}},
+				{Kind: apiref.Method, Name: "GetPoints", Type: apiref.TypeRef{Name: "Vector2, Vector2"}, Signature: "Vector2, Vector2 GetPoints()"},
+			},
+			Events: []apiref.Member{
+				{Kind: apiref.Event, Name: "HitEvent", Type: apiref.TypeRef{Name: "HitEvent"}, Description: "Occurs when hit."},
+			},
+		},
+		{
+			Name: "Component",
+			Properties: []apiref.Member{
+				{Kind: apiref.Property, Name: "Enable", Type: apiref.TypeRef{Name: "boolean"}, Badges: []string{"Sync"}},
+				{Kind: apiref.Property, Name: "UpdateAuthority", Type: apiref.TypeRef{Name: "UpdateAuthorityType", URL: enumURL}, Badges: []string{"ReadOnly"}},
+			},
+		},
+	}
+}
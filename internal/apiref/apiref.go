@@ -0,0 +1,379 @@
+// Package apiref parses converted apiReference pages into a structured API
+// model: classes with their properties, methods and events, the badges
+// attached to each member (ReadOnly, Sync, ...) and the class each inherited
+// member comes from.
+package apiref
+
+import (
+	"regexp"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/markdown"
+)
+
+// MemberKind distinguishes properties, methods and events.
+type MemberKind string
+
+const (
+	Property MemberKind = "property"
+	Method   MemberKind = "method"
+	Event    MemberKind = "event"
+)
+
+// TypeRef names a type and, when the page links it, its reference URL.
+type TypeRef struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// Param is a method parameter.
+type Param struct {
+	Name    string  `json:"name"`
+	Type    TypeRef `json:"type"`
+	Default string  `json:"default,omitempty"`
+}
+
+// Member is a property, method or event of a class.
+type Member struct {
+	Kind MemberKind `json:"kind"`
+	Name string     `json:"name"`
+	// Type is the property type or the method return type; for events it
+	// references the event type itself.
+	Type          TypeRef  `json:"type"`
+	Params        []Param  `json:"params,omitempty"`
+	Badges        []string `json:"badges,omitempty"`
+	Description   string   `json:"description,omitempty"`
+	InheritedFrom string   `json:"inheritedFrom,omitempty"`
+	Signature     string   `json:"signature"`
+}
+
+// HasBadge reports whether the member carries the given badge.
+func (m Member) HasBadge(badge string) bool {
+	for _, b := range m.Badges {
+		if strings.EqualFold(b, badge) {
+			return true
+		}
+	}
+	return false
+}
+
+// Link is an entry of a page's SeeAlso list.
+type Link struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// Class is the API model of one apiReference page.
+type Class struct {
+	Name        string   `json:"name"`
+	URL         string   `json:"url,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Badges      []string `json:"badges,omitempty"`
+	// Bases lists the classes members are inherited from, nearest first.
+	Bases      []string `json:"bases,omitempty"`
+	Properties []Member `json:"properties,omitempty"`
+	Methods    []Member `json:"methods,omitempty"`
+	Events     []Member `json:"events,omitempty"`
+	Examples   string   `json:"examples,omitempty"`
+	SeeAlso    []Link   `json:"seeAlso,omitempty"`
+}
+
+// Members returns properties, methods and events in page order.
+func (c *Class) Members() []Member {
+	out := make([]Member, 0, len(c.Properties)+len(c.Methods)+len(c.Events))
+	out = append(out, c.Properties...)
+	out = append(out, c.Methods...)
+	return append(out, c.Events...)
+}
+
+// Own filters members down to those declared on the class itself.
+func Own(members []Member) []Member {
+	var out []Member
+	for _, m := range members {
+		if m.InheritedFrom == "" {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+var (
+	linkRe      = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]*)\)`)
+	badgeImgRe  = regexp.MustCompile(`!\[[^\]]*\]\((https?://img\.shields\.io/[^)\s]*)\)`)
+	messageRe   = regexp.MustCompile(`message=([^&]*)`)
+	inheritedRe = regexp.MustCompile(`(?i)^inherited from\s+(.+)$`)
+	updateRe    = regexp.MustCompile(`^Update \d{4}-\d{2}-\d{2}`)
+)
+
+// Parse builds the API model from the Markdown of one apiReference page. It
+// reports false when the page has no Properties, Methods or Events section.
+func Parse(url, md string) (*Class, bool) {
+	c := &Class{URL: url}
+	var (
+		section   string // "", "properties", "methods", "events", "examples", "seealso"
+		inherited string
+		desc      []string
+		examples  []string
+		found     bool
+	)
+	for _, b := range markdown.Parse(md) {
+		if b.Kind == markdown.Heading {
+			title := strings.TrimSpace(b.Title)
+			if b.Level == 1 {
+				inherited = ""
+				switch strings.ToLower(title) {
+				case "properties", "methods", "events", "examples", "seealso", "see also":
+					section = strings.ReplaceAll(strings.ToLower(title), " ", "")
+					found = found || section == "properties" || section == "methods" || section == "events"
+					continue
+				}
+				if c.Name == "" {
+					c.Name = title
+					section = "description"
+					continue
+				}
+			}
+			if m := inheritedRe.FindStringSubmatch(title); m != nil {
+				inherited = strings.TrimSpace(m[1])
+				c.addBase(inherited)
+				continue
+			}
+			if section == "examples" {
+				examples = append(examples, b.Text())
+			}
+			continue
+		}
+
+		switch section {
+		case "description":
+			text := b.Text()
+			if badges := parseBadges(text); len(badges) > 0 && strings.TrimSpace(stripBadges(text)) == "" {
+				c.Badges = append(c.Badges, badges...)
+				continue
+			}
+			desc = append(desc, strings.TrimSpace(text))
+		case "properties", "methods", "events":
+			if b.Kind != markdown.Table {
+				continue
+			}
+			if m, ok := parseMemberTable(memberKind(section), b.Lines); ok {
+				m.InheritedFrom = inherited
+				switch m.Kind {
+				case Property:
+					c.Properties = append(c.Properties, m)
+				case Method:
+					c.Methods = append(c.Methods, m)
+				default:
+					c.Events = append(c.Events, m)
+				}
+			}
+		case "examples":
+			if b.Kind == markdown.Text && updateRe.MatchString(strings.TrimSpace(b.Text())) {
+				continue
+			}
+			examples = append(examples, b.Text())
+		case "seealso":
+			for _, m := range linkRe.FindAllStringSubmatch(b.Text(), -1) {
+				c.SeeAlso = append(c.SeeAlso, Link{Title: m[1], URL: m[2]})
+			}
+		}
+	}
+	if !found || c.Name == "" {
+		return nil, false
+	}
+	c.Description = strings.Join(desc, "\n\n")
+	c.Examples = strings.Join(examples, "\n\n")
+	return c, true
+}
+
+func (c *Class) addBase(name string) {
+	for _, b := range c.Bases {
+		if b == name {
+			return
+		}
+	}
+	c.Bases = append(c.Bases, name)
+}
+
+func memberKind(section string) MemberKind {
+	switch section {
+	case "properties":
+		return Property
+	case "methods":
+		return Method
+	default:
+		return Event
+	}
+}
+
+// parseMemberTable parses the two-row table mdream emits per member: the
+// header cell holds the signature and badges, the body cell the description.
+func parseMemberTable(kind MemberKind, rows []string) (Member, bool) {
+	var cells []string
+	for _, r := range rows {
+		r = strings.TrimSpace(r)
+		if strings.Trim(r, "|-: ") == "" {
+			continue
+		}
+		cells = append(cells, strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(r, "|"), "|")))
+	}
+	if len(cells) == 0 {
+		return Member{}, false
+	}
+	head := cells[0]
+	m := Member{Kind: kind, Badges: parseBadges(head)}
+	if len(cells) > 1 {
+		m.Description = strings.Join(cells[1:], "\n")
+	}
+	sig, types := plainText(stripBadges(head))
+	m.Signature = sig
+
+	switch kind {
+	case Event:
+		m.Name = sig
+		m.Type = TypeRef{Name: sig, URL: types[sig]}
+	case Property:
+		typ, name := splitLast(sig)
+		m.Name = name
+		m.Type = TypeRef{Name: typ, URL: types[typ]}
+	case Method:
+		open := strings.Index(sig, "(")
+		closing := strings.LastIndex(sig, ")")
+		if open < 0 || closing < open {
+			typ, name := splitLast(sig)
+			m.Name, m.Type = name, TypeRef{Name: typ, URL: types[typ]}
+			break
+		}
+		typ, name := splitLast(strings.TrimSpace(sig[:open]))
+		m.Name = name
+		m.Type = TypeRef{Name: typ, URL: types[typ]}
+		for _, p := range splitParams(sig[open+1 : closing]) {
+			m.Params = append(m.Params, parseParam(p, types))
+		}
+	}
+	return m, m.Name != ""
+}
+
+// parseParam parses "type name = default".
+func parseParam(s string, types map[string]string) Param {
+	var p Param
+	if i := strings.Index(s, "="); i >= 0 {
+		p.Default = strings.TrimSpace(s[i+1:])
+		s = strings.TrimSpace(s[:i])
+	}
+	typ, name := splitLast(s)
+	p.Name = name
+	p.Type = TypeRef{Name: typ, URL: types[typ]}
+	return p
+}
+
+// splitParams splits a parameter list on top-level commas, ignoring commas
+// nested in generic brackets such as func<int, string>.
+func splitParams(s string) []string {
+	var (
+		out   []string
+		depth int
+		start int
+	)
+	for i, r := range s {
+		switch r {
+		case '<', '(', '[', '{':
+			depth++
+		case '>':
+			// "->" in callback types is not a closing bracket.
+			if i > 0 && s[i-1] == '-' {
+				continue
+			}
+			depth--
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				out = append(out, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if rest := strings.TrimSpace(s[start:]); rest != "" {
+		out = append(out, rest)
+	}
+	return out
+}
+
+// splitLast splits "type words name" into the type and the last word.
+func splitLast(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	i := strings.LastIndexAny(s, " \t")
+	if i < 0 {
+		return "", s
+	}
+	return strings.TrimSpace(s[:i]), s[i+1:]
+}
+
+// plainText replaces Markdown links with their text and returns the link
+// targets keyed by text, so type names can be resolved to reference URLs.
+func plainText(s string) (string, map[string]string) {
+	types := make(map[string]string)
+	s = linkRe.ReplaceAllStringFunc(s, func(l string) string {
+		m := linkRe.FindStringSubmatch(l)
+		types[m[1]] = m[2]
+		return m[1]
+	})
+	return strings.Join(strings.Fields(s), " "), types
+}
+
+// parseBadges returns the badge names in s, from shields.io images or from
+// inline "[Badge]" tags written by convert.RewriteImages.
+func parseBadges(s string) []string {
+	var out []string
+	for _, m := range badgeImgRe.FindAllStringSubmatch(s, -1) {
+		u := strings.ReplaceAll(m[1], "&amp;", "&")
+		if mm := messageRe.FindStringSubmatch(u); mm != nil && mm[1] != "" {
+			out = append(out, mm[1])
+		}
+	}
+	tags, _ := scanTags(badgeImgRe.ReplaceAllString(s, ""))
+	return append(out, tags...)
+}
+
+// stripBadges removes badge images and inline badge tags from s.
+func stripBadges(s string) string {
+	_, rest := scanTags(badgeImgRe.ReplaceAllString(s, ""))
+	return strings.TrimSpace(rest)
+}
+
+// scanTags finds "[Tag]" occurrences that are not Markdown links or images
+// and returns the tag names together with s stripped of them.
+func scanTags(s string) ([]string, string) {
+	var (
+		tags []string
+		b    strings.Builder
+	)
+	for i := 0; i < len(s); i++ {
+		if s[i] == '[' && (i == 0 || s[i-1] != '!') {
+			if j := strings.IndexByte(s[i:], ']'); j > 1 {
+				end := i + j
+				name := s[i+1 : end]
+				if (end+1 >= len(s) || s[end+1] != '(') && isTagName(name) {
+					tags = append(tags, name)
+					i = end
+					continue
+				}
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return tags, b.String()
+}
+
+func isTagName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == ' ' || r == '.' || r == ':') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package apiref
+
+import (
+	"reflect"
+	"testing"
+)
+
+const samplePage = `# AIChaseComponent
+
+![custom](https://img.shields.io/static/v1?label=&amp;message=Preview&amp;color=slategray)
+
+AI that allows monsters to track players.
+
+# Properties
+
+| float DetectionRange |
+| --- |
+| Range of trace detection. |
+
+| [EntityRef](https://mod-developers.nexon.com/apiReference/Misc/EntityRef) TargetEntityRef ![custom](https://img.shields.io/static/v1?label=&amp;message=ReadOnly&amp;color=orange) |
+| --- |
+| Designates the Entity to be tracked. |
+
+##### inherited from Component:
+
+| boolean Enable [Sync] [HideFromInspector] |
+| --- |
+| Checks whether Component is activated or not. |
+
+# Methods
+
+| [BTNode](https://mod-developers.nexon.com/apiReference/Misc/BTNode) CreateNode([string](https://mod-developers.nexon.com/apiReference/Lua/string) nodeType, [string](https://mod-developers.nexon.com/apiReference/Lua/string) nodeName = nil, func<float> -> BehaviourTreeStatus onBehaveFunction = nil) |
+| --- |
+| Creates an Action node. |
+
+# Events
+
+| [HitEvent](https://mod-developers.nexon.com/apiReference/Events/HitEvent) |
+| --- |
+| Occurs when hit. |
+
+# Examples
+
+#### Chase
+
+` + "```\nlocal x = 1\n```" + `
+
+# SeeAlso
+
+- [AIComponent](https://mod-developers.nexon.com/apiReference/Components/AIComponent)
+
+Update 2025-08-27 PM 04:56
+`
+
+func TestParse_SampleComponent(t *testing.T) {
+	c, ok := Parse("https://example.com/AIChaseComponent", samplePage)
+	if !ok {
+		t.Fatalf("expected page to parse as a class")
+	}
+	if c.Name != "AIChaseComponent" || c.Description != "AI that allows monsters to track players." {
+		t.Fatalf("unexpected class header: %+v", c)
+	}
+	if !reflect.DeepEqual(c.Badges, []string{"Preview"}) {
+		t.Fatalf("unexpected class badges: %v", c.Badges)
+	}
+	if !reflect.DeepEqual(c.Bases, []string{"Component"}) {
+		t.Fatalf("unexpected bases: %v", c.Bases)
+	}
+
+	if len(c.Properties) != 3 {
+		t.Fatalf("expected 3 properties, got %d", len(c.Properties))
+	}
+	ref := c.Properties[1]
+	if ref.Name != "TargetEntityRef" || ref.Type.Name != "EntityRef" || ref.Type.URL == "" || !ref.HasBadge("readonly") {
+		t.Fatalf("unexpected property: %+v", ref)
+	}
+	enable := c.Properties[2]
+	if enable.InheritedFrom != "Component" || !reflect.DeepEqual(enable.Badges, []string{"Sync", "HideFromInspector"}) || enable.Signature != "boolean Enable" {
+		t.Fatalf("unexpected inherited property: %+v", enable)
+	}
+
+	if len(c.Methods) != 1 {
+		t.Fatalf("expected 1 method, got %d", len(c.Methods))
+	}
+	m := c.Methods[0]
+	if m.Name != "CreateNode" || m.Type.Name != "BTNode" || len(m.Params) != 3 {
+		t.Fatalf("unexpected method: %+v", m)
+	}
+	want := Param{Name: "onBehaveFunction", Type: TypeRef{Name: "func<float> -> BehaviourTreeStatus"}, Default: "nil"}
+	if !reflect.DeepEqual(m.Params[2], want) {
+		t.Fatalf("unexpected callback param: %+v", m.Params[2])
+	}
+	if m.Params[0].Type.URL != "https://mod-developers.nexon.com/apiReference/Lua/string" {
+		t.Fatalf("param type URL not resolved: %+v", m.Params[0])
+	}
+
+	if len(c.Events) != 1 || c.Events[0].Name != "HitEvent" || c.Events[0].Description != "Occurs when hit." {
+		t.Fatalf("unexpected events: %+v", c.Events)
+	}
+	if c.Examples != "#### Chase\n\n```\nlocal x = 1\n```" {
+		t.Fatalf("unexpected examples: %q", c.Examples)
+	}
+	if len(c.SeeAlso) != 1 || c.SeeAlso[0].Title != "AIComponent" {
+		t.Fatalf("unexpected see also: %+v", c.SeeAlso)
+	}
+}
+
+func TestParse_NonAPIPage(t *testing.T) {
+	if _, ok := Parse("u", "# Workspace\n\nIntroducing Workspace."); ok {
+		t.Fatalf("reference page should not parse as a class")
+	}
+}
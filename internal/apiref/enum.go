@@ -0,0 +1,145 @@
+package apiref
+
+import (
+	"strconv"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/markdown"
+)
+
+// EnumValue is a member of an enum.
+type EnumValue struct {
+	Name string `json:"name"`
+	// Value is the member's number as written on the page, or "" when the
+	// page does not give one.
+	Value       string `json:"value,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Enum is the model of one apiReference/Enums page.
+type Enum struct {
+	Name        string      `json:"name"`
+	URL         string      `json:"url,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Values      []EnumValue `json:"values"`
+}
+
+// IsEnumURL reports whether url is an enum page of the API reference.
+func IsEnumURL(url string) bool {
+	return strings.Contains(url, "/apiReference/Enums/")
+}
+
+// ParseEnum builds the model of an enum page from its Markdown. The values
+// are read from the tables of its Members section, either one two-row table
+// per value as mdream writes members, or one table with a Name column. It
+// reports false when the page lists no value.
+func ParseEnum(url, md string) (*Enum, bool) {
+	e := &Enum{URL: url}
+	var (
+		section string // "", "description", "values"
+		desc    []string
+	)
+	for _, b := range markdown.Parse(md) {
+		if b.Kind == markdown.Heading {
+			title := strings.TrimSpace(b.Title)
+			if b.Level != 1 {
+				continue
+			}
+			switch strings.ToLower(title) {
+			case "members", "values", "fields", "enum values":
+				section = "values"
+			default:
+				if e.Name == "" {
+					e.Name, section = title, "description"
+				} else {
+					section = ""
+				}
+			}
+			continue
+		}
+		switch section {
+		case "description":
+			text := b.Text()
+			if len(parseBadges(text)) > 0 && strings.TrimSpace(stripBadges(text)) == "" {
+				continue
+			}
+			desc = append(desc, strings.TrimSpace(text))
+		case "values":
+			if b.Kind == markdown.Table {
+				e.Values = append(e.Values, parseEnumTable(b.Lines)...)
+			}
+		}
+	}
+	if e.Name == "" || len(e.Values) == 0 {
+		return nil, false
+	}
+	e.Description = strings.Join(desc, "\n\n")
+	return e, true
+}
+
+// parseEnumTable returns the values of one table of a Members section.
+func parseEnumTable(rows []string) []EnumValue {
+	var cells [][]string
+	for _, r := range rows {
+		r = strings.TrimSpace(r)
+		if strings.Trim(r, "|-: ") == "" {
+			continue
+		}
+		var row []string
+		for _, c := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(r, "|"), "|"), "|") {
+			text, _ := plainText(stripBadges(c))
+			row = append(row, text)
+		}
+		cells = append(cells, row)
+	}
+	if len(cells) == 0 {
+		return nil
+	}
+
+	// A table with a header row naming its columns lists one value per row
+	if head := cells[0]; len(head) > 1 && strings.EqualFold(head[0], "name") {
+		col := func(row []string, names ...string) string {
+			for i, h := range head {
+				for _, n := range names {
+					if strings.EqualFold(h, n) && i < len(row) {
+						return row[i]
+					}
+				}
+			}
+			return ""
+		}
+		var out []EnumValue
+		for _, row := range cells[1:] {
+			if v := (EnumValue{Name: row[0], Value: col(row, "value", "number"), Description: col(row, "description")}); v.Name != "" {
+				out = append(out, v)
+			}
+		}
+		return out
+	}
+
+	// Otherwise the header cell names one value, as "Name", "Name = 1" or
+	// "Name 1", and the rows below describe it
+	v := EnumValue{}
+	head := cells[0][0]
+	if name, value, ok := strings.Cut(head, "="); ok {
+		v.Name, v.Value = strings.TrimSpace(name), strings.TrimSpace(value)
+	} else if name, value := splitLast(head); name != "" && isInt(value) {
+		v.Name, v.Value = name, value
+	} else {
+		v.Name = head
+	}
+	if v.Name == "" || strings.ContainsAny(v.Name, " \t") {
+		return nil
+	}
+	var desc []string
+	for _, row := range cells[1:] {
+		desc = append(desc, strings.Join(row, " "))
+	}
+	v.Description = strings.Join(desc, "\n")
+	return []EnumValue{v}
+}
+
+func isInt(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
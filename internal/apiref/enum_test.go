@@ -0,0 +1,55 @@
+package apiref
+
+import (
+	"reflect"
+	"testing"
+)
+
+const sampleEnumPage = `# UpdateAuthorityType
+
+![custom](https://img.shields.io/static/v1?label=&amp;message=ScriptOnly&amp;color=slategray)
+
+Specifies where a component is updated.
+
+# Members
+
+| Server = 0 |
+| --- |
+| Updated on the server. |
+
+| Client = 1 |
+| --- |
+| Updated on the [client](https://example.com/docs/?postId=1). |
+`
+
+func TestParseEnum(t *testing.T) {
+	e, ok := ParseEnum("https://example.com/apiReference/Enums/UpdateAuthorityType", sampleEnumPage)
+	if !ok {
+		t.Fatal("ParseEnum reported no enum")
+	}
+	if e.Name != "UpdateAuthorityType" || e.Description != "Specifies where a component is updated." {
+		t.Fatalf("unexpected enum %+v", e)
+	}
+	want := []EnumValue{
+		{Name: "Server", Value: "0", Description: "Updated on the server."},
+		{Name: "Client", Value: "1", Description: "Updated on the client."},
+	}
+	if !reflect.DeepEqual(e.Values, want) {
+		t.Fatalf("Values = %+v, want %+v", e.Values, want)
+	}
+}
+
+func TestParseEnum_ColumnTable(t *testing.T) {
+	md := "# SpriteDrawMode\n\n# Values\n\n| Name | Value | Description |\n| --- | --- | --- |\n| Simple | 0 | Draws as is. |\n| Tiled | 1 | Repeats the sprite. |\n"
+	e, ok := ParseEnum("", md)
+	if !ok {
+		t.Fatal("ParseEnum reported no enum")
+	}
+	want := []EnumValue{{Name: "Simple", Value: "0", Description: "Draws as is."}, {Name: "Tiled", Value: "1", Description: "Repeats the sprite."}}
+	if !reflect.DeepEqual(e.Values, want) {
+		t.Fatalf("Values = %+v, want %+v", e.Values, want)
+	}
+	if _, ok := ParseEnum("", "# Empty\n\nNo values.\n"); ok {
+		t.Fatal("a page without values should not parse")
+	}
+}
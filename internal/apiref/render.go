@@ -0,0 +1,345 @@
+package apiref
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Style selects how API pages are written to Markdown.
+type Style string
+
+const (
+	// StyleMdream keeps mdream's output: one two-row table per member.
+	StyleMdream Style = "mdream"
+	// StyleHeadings writes each member as "### Name: type [Badge]" followed
+	// by its description.
+	StyleHeadings Style = "headings"
+	// StyleTable writes one table per section with a row per member.
+	StyleTable Style = "table"
+)
+
+// Styles lists the supported styles, default first.
+var Styles = []Style{StyleMdream, StyleHeadings, StyleTable}
+
+// ParseStyle returns the style with the given name.
+func ParseStyle(s string) (Style, error) {
+	for _, st := range Styles {
+		if string(st) == s {
+			return st, nil
+		}
+	}
+	return "", fmt.Errorf("unknown API style %q (want mdream, headings or table)", s)
+}
+
+// Inheritance selects how members inherited from base classes are written.
+type Inheritance string
+
+const (
+	// InheritGrouped keeps the page layout: inherited members follow the
+	// class's own, grouped under "inherited from" headings.
+	InheritGrouped Inheritance = "grouped"
+	// InheritFlatten writes inherited members only on their base class and
+	// replaces them with an "Inherits: A → B" line.
+	InheritFlatten Inheritance = "flatten"
+	// InheritExpand lists every member in one run, each inherited member
+	// annotated with the class it comes from.
+	InheritExpand Inheritance = "expand"
+)
+
+// Inheritances lists the supported inheritance modes, default first.
+var Inheritances = []Inheritance{InheritGrouped, InheritFlatten, InheritExpand}
+
+// ParseInheritance returns the inheritance mode with the given name.
+func ParseInheritance(s string) (Inheritance, error) {
+	for _, in := range Inheritances {
+		if string(in) == s {
+			return in, nil
+		}
+	}
+	return "", fmt.Errorf("unknown inheritance mode %q (want grouped, flatten or expand)", s)
+}
+
+// Renderer writes API classes as Markdown.
+type Renderer struct {
+	Style       Style
+	Inheritance Inheritance
+	// Known holds the names of the classes that have a page of their own.
+	// InheritFlatten only drops members inherited from known classes, so
+	// nothing is lost when a base class page is missing.
+	Known map[string]bool
+}
+
+// RenderOption configures a Renderer.
+type RenderOption func(*Renderer)
+
+// WithStyle sets the member style.
+func WithStyle(s Style) RenderOption {
+	return func(r *Renderer) { r.Style = s }
+}
+
+// WithInheritance sets how inherited members are written.
+func WithInheritance(in Inheritance) RenderOption {
+	return func(r *Renderer) { r.Inheritance = in }
+}
+
+// WithKnownClasses sets the classes that have a page of their own.
+func WithKnownClasses(names []string) RenderOption {
+	return func(r *Renderer) {
+		r.Known = make(map[string]bool, len(names))
+		for _, n := range names {
+			r.Known[n] = true
+		}
+	}
+}
+
+// NewRenderer constructs a Renderer that keeps mdream's layout unless told
+// otherwise.
+func NewRenderer(opts ...RenderOption) *Renderer {
+	r := &Renderer{Style: StyleMdream, Inheritance: InheritGrouped}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+	return r
+}
+
+// Markdown rewrites the Markdown of an apiReference page. Pages that are not
+// API pages are returned unchanged, and so is every page when the renderer
+// keeps both mdream's style and its grouping.
+func (r *Renderer) Markdown(url, md string) string {
+	if r.Style == StyleMdream && r.Inheritance == InheritGrouped {
+		return md
+	}
+	c, ok := Parse(url, md)
+	if !ok {
+		return md
+	}
+	return r.Render(c)
+}
+
+// Render writes the class as Markdown.
+func (r *Renderer) Render(c *Class) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", c.Name)
+	if len(c.Badges) > 0 {
+		fmt.Fprintf(&b, "%s\n\n", badgeTags(c.Badges))
+	}
+	if c.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", c.Description)
+	}
+	if r.Inheritance == InheritFlatten && len(c.Bases) > 0 {
+		fmt.Fprintf(&b, "Inherits: %s\n\n", strings.Join(c.Bases, " → "))
+	}
+	for _, sec := range []struct {
+		title   string
+		members []Member
+	}{
+		{"Properties", c.Properties},
+		{"Methods", c.Methods},
+		{"Events", c.Events},
+	} {
+		groups := r.groups(sec.members)
+		if len(groups) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "# %s\n\n", sec.title)
+		for _, g := range groups {
+			r.writeGroup(&b, g)
+		}
+	}
+	if c.Examples != "" {
+		fmt.Fprintf(&b, "# Examples\n\n%s\n\n", c.Examples)
+	}
+	if len(c.SeeAlso) > 0 {
+		b.WriteString("# SeeAlso\n\n")
+		for _, l := range c.SeeAlso {
+			fmt.Fprintf(&b, "- [%s](%s)\n", l.Title, l.URL)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// groups arranges a section's members for the renderer's inheritance mode.
+func (r *Renderer) groups(members []Member) []origin {
+	switch r.Inheritance {
+	case InheritExpand:
+		if len(members) == 0 {
+			return nil
+		}
+		return []origin{{members: members, expanded: true}}
+	case InheritFlatten:
+		var kept []Member
+		for _, m := range members {
+			if m.InheritedFrom == "" || !r.Known[m.InheritedFrom] {
+				kept = append(kept, m)
+			}
+		}
+		return groupByOrigin(kept)
+	default:
+		return groupByOrigin(members)
+	}
+}
+
+func (r *Renderer) writeGroup(b *strings.Builder, g origin) {
+	switch r.Style {
+	case StyleTable:
+		if g.from != "" {
+			fmt.Fprintf(b, "## Inherited from %s\n\n", g.from)
+		}
+		writeTable(b, g.members, g.expanded)
+	case StyleHeadings:
+		if g.from != "" {
+			fmt.Fprintf(b, "## Inherited from %s\n\n", g.from)
+		}
+		for _, m := range g.members {
+			h := compactHeading(m)
+			if g.expanded && m.InheritedFrom != "" {
+				h += " (from " + m.InheritedFrom + ")"
+			}
+			fmt.Fprintf(b, "### %s\n\n", h)
+			if m.Description != "" {
+				fmt.Fprintf(b, "%s\n\n", strings.ReplaceAll(m.Description, `\|`, "|"))
+			}
+		}
+	default:
+		if g.from != "" {
+			fmt.Fprintf(b, "##### inherited from %s:\n\n", g.from)
+		}
+		for _, m := range g.members {
+			head := linkedSignature(m)
+			if len(m.Badges) > 0 {
+				head += " " + badgeTags(m.Badges)
+			}
+			if g.expanded && m.InheritedFrom != "" {
+				head += " (from " + m.InheritedFrom + ")"
+			}
+			fmt.Fprintf(b, "| %s |\n| --- |\n| %s |\n\n", cell(head), cell(m.Description))
+		}
+	}
+}
+
+// origin is a run of members written together: the members inherited from
+// one class, or with expanded set, every member of a section.
+type origin struct {
+	from     string
+	members  []Member
+	expanded bool
+}
+
+// groupByOrigin splits members into runs sharing InheritedFrom, keeping page
+// order: own members first, then each base class.
+func groupByOrigin(members []Member) []origin {
+	var out []origin
+	for _, m := range members {
+		if n := len(out); n > 0 && out[n-1].from == m.InheritedFrom {
+			out[n-1].members = append(out[n-1].members, m)
+			continue
+		}
+		out = append(out, origin{from: m.InheritedFrom, members: []Member{m}})
+	}
+	return out
+}
+
+// compactHeading returns "Name: type [Badge]" for properties,
+// "Name(type param = default): returns [Badge]" for methods and the event
+// name for events.
+func compactHeading(m Member) string {
+	var s string
+	switch m.Kind {
+	case Method:
+		s = m.Name + "(" + paramList(m.Params) + ")"
+		if m.Type.Name != "" {
+			s += ": " + m.Type.Name
+		}
+	case Property:
+		s = m.Name
+		if m.Type.Name != "" {
+			s += ": " + m.Type.Name
+		}
+	default:
+		s = m.Name
+	}
+	if len(m.Badges) > 0 {
+		s += " " + badgeTags(m.Badges)
+	}
+	return s
+}
+
+// writeTable writes members as one table: member, type, badges, description
+// and, when expanded, the class each member comes from.
+func writeTable(b *strings.Builder, members []Member, expanded bool) {
+	if expanded {
+		b.WriteString("| Name | Type | Badges | From | Description |\n| --- | --- | --- | --- | --- |\n")
+	} else {
+		b.WriteString("| Name | Type | Badges | Description |\n| --- | --- | --- | --- |\n")
+	}
+	for _, m := range members {
+		name := m.Name
+		if m.Kind == Method {
+			name += "(" + paramList(m.Params) + ")"
+		}
+		typ := m.Type.Name
+		if m.Kind == Event {
+			typ = ""
+		}
+		fmt.Fprintf(b, "| %s | %s | %s | ", cell(name), cell(typ), cell(strings.Join(m.Badges, ", ")))
+		if expanded {
+			fmt.Fprintf(b, "%s | ", cell(m.InheritedFrom))
+		}
+		fmt.Fprintf(b, "%s |\n", cell(m.Description))
+	}
+	b.WriteString("\n")
+}
+
+// linkedSignature rebuilds the signature of m as mdream writes it, with the
+// return, property, event and parameter types linked to their reference
+// pages.
+func linkedSignature(m Member) string {
+	switch m.Kind {
+	case Event:
+		return typeLink(m.Type)
+	case Method:
+		params := make([]string, len(m.Params))
+		for i, p := range m.Params {
+			params[i] = strings.TrimSpace(typeLink(p.Type) + " " + p.Name)
+			if p.Default != "" {
+				params[i] += " = " + p.Default
+			}
+		}
+		return strings.TrimSpace(typeLink(m.Type)+" "+m.Name) + "(" + strings.Join(params, ", ") + ")"
+	default:
+		return strings.TrimSpace(typeLink(m.Type) + " " + m.Name)
+	}
+}
+
+// typeLink writes t as a Markdown link when the page linked it.
+func typeLink(t TypeRef) string {
+	if t.URL == "" {
+		return t.Name
+	}
+	return "[" + t.Name + "](" + t.URL + ")"
+}
+
+func paramList(params []Param) string {
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = strings.TrimSpace(p.Type.Name + " " + p.Name)
+		if p.Default != "" {
+			parts[i] += " = " + p.Default
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func badgeTags(badges []string) string {
+	return "[" + strings.Join(badges, "] [") + "]"
+}
+
+// cell escapes pipes and folds line breaks so s fits in one table cell.
+// Descriptions come from table cells and may already be escaped.
+func cell(s string) string {
+	s = strings.ReplaceAll(strings.ReplaceAll(s, `\|`, "|"), "|", `\|`)
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "\n", " ")), " ")
+}
@@ -0,0 +1,114 @@
+package apiref
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender_Headings(t *testing.T) {
+	out := NewRenderer(WithStyle(StyleHeadings)).Markdown("u", samplePage)
+	for _, want := range []string{
+		"# AIChaseComponent\n\n[Preview]\n\nAI that allows monsters to track players.\n\n# Properties\n\n### DetectionRange: float\n\nRange of trace detection.\n\n",
+		"### TargetEntityRef: EntityRef [ReadOnly]\n\nDesignates the Entity to be tracked.",
+		"## Inherited from Component\n\n### Enable: boolean [Sync] [HideFromInspector]\n\n",
+		"# Methods\n\n### CreateNode(string nodeType, string nodeName = nil, func<float> -> BehaviourTreeStatus onBehaveFunction = nil): BTNode\n\n",
+		"# Events\n\n### HitEvent\n\nOccurs when hit.",
+		"# Examples\n\n#### Chase\n\n```\nlocal x = 1\n```\n\n",
+		"# SeeAlso\n\n- [AIComponent](https://mod-developers.nexon.com/apiReference/Components/AIComponent)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "| --- |") || strings.Contains(out, "shields.io") {
+		t.Fatalf("compact output should not contain member tables or badge images:\n%s", out)
+	}
+}
+
+func TestRender_Table(t *testing.T) {
+	out := NewRenderer(WithStyle(StyleTable)).Markdown("u", samplePage)
+	want := "# Properties\n\n| Name | Type | Badges | Description |\n| --- | --- | --- | --- |\n" +
+		"| DetectionRange | float |  | Range of trace detection. |\n" +
+		"| TargetEntityRef | EntityRef | ReadOnly | Designates the Entity to be tracked. |\n\n" +
+		"## Inherited from Component\n\n| Name | Type | Badges | Description |\n| --- | --- | --- | --- |\n" +
+		"| Enable | boolean | Sync, HideFromInspector | Checks whether Component is activated or not. |\n"
+	if !strings.Contains(out, want) {
+		t.Fatalf("expected %q in output, got:\n%s", want, out)
+	}
+	if !strings.Contains(out, "| HitEvent |  |  | Occurs when hit. |") {
+		t.Fatalf("event row missing:\n%s", out)
+	}
+}
+
+func TestRenderer_Passthrough(t *testing.T) {
+	if out := NewRenderer().Markdown("u", samplePage); out != samplePage {
+		t.Fatalf("the default renderer should keep the page unchanged")
+	}
+	page := "# Workspace\n\nIntroducing Workspace."
+	if out := NewRenderer(WithStyle(StyleTable)).Markdown("u", page); out != page {
+		t.Fatalf("non-API pages should be unchanged, got %q", out)
+	}
+}
+
+func TestRender_Flatten(t *testing.T) {
+	c, _ := Parse("u", samplePage)
+	out := NewRenderer(WithInheritance(InheritFlatten), WithKnownClasses([]string{"Component"})).Render(c)
+	if !strings.Contains(out, "players.\n\nInherits: Component\n\n# Properties") {
+		t.Fatalf("expected an Inherits line, got:\n%s", out)
+	}
+	if strings.Contains(out, "Enable") || strings.Contains(out, "inherited from") {
+		t.Fatalf("members of a known base class should be dropped:\n%s", out)
+	}
+	if !strings.Contains(out, "| [EntityRef](https://mod-developers.nexon.com/apiReference/Misc/EntityRef) TargetEntityRef [ReadOnly] |\n| --- |\n| Designates the Entity to be tracked. |") {
+		t.Fatalf("own members should keep the mdream form and its links:\n%s", out)
+	}
+	method := "| [BTNode](https://mod-developers.nexon.com/apiReference/Misc/BTNode) CreateNode([string](https://mod-developers.nexon.com/apiReference/Lua/string) nodeType, " +
+		"[string](https://mod-developers.nexon.com/apiReference/Lua/string) nodeName = nil, func<float> -> BehaviourTreeStatus onBehaveFunction = nil) |"
+	if !strings.Contains(out, method) || !strings.Contains(out, "| [HitEvent](https://mod-developers.nexon.com/apiReference/Events/HitEvent) |") {
+		t.Fatalf("method and event signatures should keep their type links:\n%s", out)
+	}
+
+	// Without a page of its own the base class's members stay
+	out = NewRenderer(WithInheritance(InheritFlatten)).Render(c)
+	if !strings.Contains(out, "##### inherited from Component:\n\n| boolean Enable [Sync] [HideFromInspector] |") {
+		t.Fatalf("members of an unknown base class should be kept:\n%s", out)
+	}
+}
+
+func TestRender_Expand(t *testing.T) {
+	c, _ := Parse("u", samplePage)
+	out := NewRenderer(WithStyle(StyleHeadings), WithInheritance(InheritExpand)).Render(c)
+	want := "### TargetEntityRef: EntityRef [ReadOnly]\n\nDesignates the Entity to be tracked.\n\n" +
+		"### Enable: boolean [Sync] [HideFromInspector] (from Component)\n\n"
+	if !strings.Contains(out, want) {
+		t.Fatalf("expected %q in output, got:\n%s", want, out)
+	}
+	if strings.Contains(out, "Inherited from") {
+		t.Fatalf("expanded output should not group by class:\n%s", out)
+	}
+
+	out = NewRenderer(WithStyle(StyleTable), WithInheritance(InheritExpand)).Render(c)
+	if !strings.Contains(out, "| Name | Type | Badges | From | Description |") ||
+		!strings.Contains(out, "| Enable | boolean | Sync, HideFromInspector | Component | Checks") ||
+		!strings.Contains(out, "| DetectionRange | float |  |  | Range") {
+		t.Fatalf("expanded table should have a From column:\n%s", out)
+	}
+}
+
+func TestCell(t *testing.T) {
+	if got := cell("a | b \\| c\nd"); got != `a \| b \| c d` {
+		t.Fatalf("cell = %q", got)
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	if in, err := ParseInheritance("flatten"); err != nil || in != InheritFlatten {
+		t.Fatalf("ParseInheritance(flatten) = %q, %v", in, err)
+	}
+	if s, err := ParseStyle("table"); err != nil || s != StyleTable {
+		t.Fatalf("ParseStyle(table) = %q, %v", s, err)
+	}
+	if _, err := ParseStyle("yaml"); err == nil {
+		t.Fatalf("expected an error for an unknown style")
+	}
+}
@@ -0,0 +1,162 @@
+// Package assemble concatenates converted documents into one Markdown file
+// with a coherent outline. Each page repeats its own "#" title and uses "#"
+// for its sections, so plain concatenation yields dozens of top-level
+// "Course Introduction" headings; the assembler instead nests every document
+// under headings for its nav breadcrumb, demotes its headings accordingly
+// and opens the file with a table of contents.
+package assemble
+
+import (
+	"fmt"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/markdown"
+	"maplestory-world-llms-txt/internal/section"
+)
+
+// Part is a document and the Markdown to publish for it, which differs from
+// Document.Markdown when the API pages are re-rendered.
+type Part struct {
+	Doc      document.Document
+	Markdown string
+}
+
+// Assembler holds the assembly configuration.
+type Assembler struct {
+	// TOC writes a table of contents at the top of the file.
+	TOC bool
+	// TOCDepth is how many levels of sections below each document title the
+	// table of contents lists; 0 lists the documents only.
+	TOCDepth int
+}
+
+// Option configures an Assembler.
+type Option func(*Assembler)
+
+// WithTOC turns the table of contents on or off.
+func WithTOC(on bool) Option {
+	return func(a *Assembler) { a.TOC = on }
+}
+
+// WithTOCDepth sets how many section levels below each document title the
+// table of contents lists. Negative values are clamped to 0.
+func WithTOCDepth(n int) Option {
+	if n < 0 {
+		n = 0
+	}
+	return func(a *Assembler) { a.TOCDepth = n }
+}
+
+// NewAssembler constructs an Assembler that writes a table of contents of the
+// documents, adjusted by the provided options.
+func NewAssembler(opts ...Option) *Assembler {
+	a := &Assembler{TOC: true}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(a)
+		}
+	}
+	return a
+}
+
+// Heading is one heading of the assembled outline with the content up to
+// the next heading.
+type Heading struct {
+	Level int
+	Title string
+	// Anchor is empty for nav group headings, which belong to no document.
+	Anchor string
+	// Doc is the index of the part the heading belongs to, -1 for nav groups.
+	Doc int
+	// Depth counts the levels below the document title: 0 for the title
+	// itself, -1 for nav groups.
+	Depth int
+	Body  string
+}
+
+// Outline returns the headings of the assembled parts, in order. Documents
+// sharing a breadcrumb prefix share its group headings, which are placed
+// where the breadcrumb first differs from the previous document's; each
+// document's section tree follows with its levels normalized below them.
+func Outline(parts []Part) []Heading {
+	docs := make([]document.Document, len(parts))
+	for i, p := range parts {
+		docs[i] = p.Doc
+		docs[i].Markdown = p.Markdown
+	}
+
+	var (
+		out  []Heading
+		prev []string
+	)
+	for doc, t := range section.ParseAll(docs) {
+		n := 0
+		for n < len(prev) && n < len(t.Breadcrumb) && prev[n] == t.Breadcrumb[n] {
+			n++
+		}
+		for j := n; j < len(t.Breadcrumb); j++ {
+			out = append(out, Heading{Level: min(j+1, section.MaxLevel), Title: t.Breadcrumb[j], Doc: -1, Depth: -1})
+		}
+		prev = t.Breadcrumb
+
+		depths := make([]int, len(t.Sections))
+		for i, s := range t.Sections {
+			if s.Parent >= 0 {
+				depths[i] = depths[s.Parent] + 1
+			}
+			out = append(out, Heading{Level: s.Level, Title: s.Title, Anchor: s.Anchor, Doc: doc, Depth: depths[i], Body: s.Body})
+		}
+	}
+	return out
+}
+
+// Assemble returns the concatenated Markdown of parts, in the order of
+// Outline. Every document and section heading carries an HTML anchor named
+// after its section anchor, e.g. <a id="workspace/searching-workspace"></a>.
+// The result is normalized, so identical parts always yield identical bytes.
+func (a *Assembler) Assemble(parts []Part) string {
+	headings := Outline(parts)
+	var b strings.Builder
+	if a.TOC {
+		a.writeTOC(&b, headings)
+	}
+	for _, h := range headings {
+		b.WriteString(strings.Repeat("#", h.Level) + " ")
+		if h.Anchor != "" {
+			fmt.Fprintf(&b, `<a id="%s"></a>`, h.Anchor)
+		}
+		b.WriteString(h.Title + "\n\n")
+		if h.Body != "" {
+			b.WriteString(h.Body + "\n\n")
+		}
+	}
+	return markdown.Normalize(b.String())
+}
+
+// writeTOC writes the table of contents as a nested list: nav groups as
+// plain items, documents and sections as links to their anchors.
+func (a *Assembler) writeTOC(b *strings.Builder, headings []Heading) {
+	var lines []string
+	for _, h := range headings {
+		if h.Depth > a.TOCDepth {
+			continue
+		}
+		item := h.Title
+		if h.Anchor != "" {
+			item = fmt.Sprintf("[%s](#%s)", escapeLinkText(h.Title), h.Anchor)
+		}
+		lines = append(lines, strings.Repeat("  ", h.Level-1)+"- "+item)
+	}
+	if len(lines) == 0 {
+		return
+	}
+	b.WriteString("**Contents**\n\n")
+	b.WriteString(strings.Join(lines, "\n"))
+	b.WriteString("\n\n")
+}
+
+// escapeLinkText escapes the brackets that would end a link text early.
+func escapeLinkText(s string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(s)
+}
@@ -0,0 +1,126 @@
+package assemble
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/markdown"
+)
+
+func sampleParts() []Part {
+	doc := func(title string, crumb []string, md string) Part {
+		return Part{Doc: document.Document{Title: title, Breadcrumb: crumb}, Markdown: md}
+	}
+	return []Part{
+		doc("Workspace", []string{"Maker", "Basic Guide"},
+			"# Workspace\n\nBadges.\n\n# Course Introduction\n\nIntro.\n\n##### Reference Guide\n\nLinks.\n\n# Searching Workspace\n\nSearch."),
+		doc("Hierarchy", []string{"Maker", "Basic Guide"},
+			"# Hierarchy\n\n# Course Introduction\n\nIntro."),
+		doc("Scripting", []string{"Maker", "Scripts"},
+			"# Scripting\n\n## [Lua] Basics\n\nText."),
+	}
+}
+
+func headings(md string) []markdown.Block {
+	var out []markdown.Block
+	for _, b := range markdown.Parse(md) {
+		if b.Kind == markdown.Heading {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+func TestAssemble_NestsDocumentsUnderBreadcrumb(t *testing.T) {
+	got := NewAssembler(WithTOC(false)).Assemble(sampleParts())
+
+	want := []string{
+		"# Maker",
+		"## Basic Guide",
+		`### <a id="workspace"></a>Workspace`,
+		`#### <a id="workspace/course-introduction"></a>Course Introduction`,
+		`##### <a id="workspace/reference-guide"></a>Reference Guide`,
+		`#### <a id="workspace/searching-workspace"></a>Searching Workspace`,
+		`### <a id="hierarchy"></a>Hierarchy`,
+		`#### <a id="hierarchy/course-introduction"></a>Course Introduction`,
+		"## Scripts",
+		`### <a id="scripting"></a>Scripting`,
+		`#### <a id="scripting/lua-basics"></a>[Lua] Basics`,
+	}
+	hs := headings(got)
+	if len(hs) != len(want) {
+		t.Fatalf("expected %d headings, got %d:\n%s", len(want), len(hs), got)
+	}
+	for i, h := range hs {
+		if h.Text() != want[i] {
+			t.Errorf("heading %d = %q, want %q", i, h.Text(), want[i])
+		}
+	}
+	// Levels never skip on the way down
+	for i := 1; i < len(hs); i++ {
+		if hs[i].Level > hs[i-1].Level+1 {
+			t.Errorf("heading %q jumps from level %d to %d", hs[i].Title, hs[i-1].Level, hs[i].Level)
+		}
+	}
+	if !strings.Contains(got, "Workspace\n\nBadges.\n\n####") || !strings.HasSuffix(got, "Text.\n") {
+		t.Errorf("bodies must follow their headings:\n%s", got)
+	}
+}
+
+func TestAssemble_WritesTOC(t *testing.T) {
+	got := NewAssembler().Assemble(sampleParts())
+	toc, _, ok := strings.Cut(got, "\n\n# Maker\n")
+	if !ok {
+		t.Fatalf("expected the table of contents before the first heading:\n%s", got)
+	}
+	want := "**Contents**\n\n" +
+		"- Maker\n" +
+		"  - Basic Guide\n" +
+		"    - [Workspace](#workspace)\n" +
+		"    - [Hierarchy](#hierarchy)\n" +
+		"  - Scripts\n" +
+		"    - [Scripting](#scripting)"
+	if toc != want {
+		t.Fatalf("unexpected table of contents:\n%s\nwant:\n%s", toc, want)
+	}
+
+	deep := NewAssembler(WithTOCDepth(1)).Assemble(sampleParts())
+	for _, line := range []string{
+		"      - [Course Introduction](#workspace/course-introduction)\n",
+		"      - [\\[Lua\\] Basics](#scripting/lua-basics)\n",
+	} {
+		if !strings.Contains(deep, line) {
+			t.Errorf("expected %q in the depth-1 contents:\n%s", line, deep)
+		}
+	}
+	if strings.Contains(deep, "(#workspace/reference-guide)") {
+		t.Errorf("depth 1 must not list subsections")
+	}
+}
+
+func TestAssemble_WithoutBreadcrumbUsesTitleAsTopLevel(t *testing.T) {
+	parts := []Part{
+		{Doc: document.Document{Title: "Scene"}, Markdown: "# Scene\n\n# Scene\n\nBody."},
+		{Doc: document.Document{Title: "Scene"}, Markdown: "Untitled body.\n\n### Deep\n\nText."},
+	}
+	got := NewAssembler(WithTOC(false)).Assemble(parts)
+	want := "# <a id=\"scene\"></a>Scene\n\n## <a id=\"scene/scene\"></a>Scene\n\nBody.\n\n" +
+		"# <a id=\"scene-1\"></a>Scene\n\nUntitled body.\n\n## <a id=\"scene-1/deep\"></a>Deep\n\nText.\n"
+	if got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestOutline_RecordsDocumentAndDepth(t *testing.T) {
+	hs := Outline(sampleParts())
+	var got []string
+	for _, h := range hs {
+		got = append(got, fmt.Sprintf("%d/%d", h.Doc, h.Depth))
+	}
+	want := "-1/-1 -1/-1 0/0 0/1 0/2 0/1 1/0 1/1 -1/-1 2/0 2/1"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("Outline doc/depth = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,374 @@
+// Package chunk splits converted Markdown documents into token-bounded chunks
+// for retrieval systems. Chunks follow heading boundaries and never cut a
+// fenced code block or a table row in half.
+package chunk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/markdown"
+)
+
+// Chunk is a retrieval unit cut from a single document.
+type Chunk struct {
+	ID    string `json:"id"`
+	Index int    `json:"index"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	// Breadcrumb is the navigation path of the document followed by its
+	// title and the headings enclosing the chunk.
+	Breadcrumb []string `json:"breadcrumb"`
+	Text       string   `json:"text"`
+	Tokens     int      `json:"tokens"`
+}
+
+// Chunker holds the splitting configuration.
+type Chunker struct {
+	MaxTokens int
+	Overlap   int
+	Count     func(string) int
+}
+
+// Option configures a Chunker.
+type Option func(*Chunker)
+
+// WithMaxTokens sets the token budget per chunk. Values below 1 keep the default.
+func WithMaxTokens(n int) Option {
+	return func(c *Chunker) {
+		if n > 0 {
+			c.MaxTokens = n
+		}
+	}
+}
+
+// WithOverlap sets how many tokens of trailing text a chunk repeats from the
+// previous chunk of the same section. Negative values are clamped to 0.
+func WithOverlap(n int) Option {
+	if n < 0 {
+		n = 0
+	}
+	return func(c *Chunker) { c.Overlap = n }
+}
+
+// WithTokenCounter replaces the default EstimateTokens counter, e.g. with a
+// real tokenizer for the target model.
+func WithTokenCounter(f func(string) int) Option {
+	return func(c *Chunker) {
+		if f != nil {
+			c.Count = f
+		}
+	}
+}
+
+// NewChunker constructs a Chunker with a 512 token budget and no overlap,
+// adjusted by the provided options.
+func NewChunker(opts ...Option) *Chunker {
+	c := &Chunker{MaxTokens: 512, Count: EstimateTokens}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+	if c.Overlap >= c.MaxTokens {
+		c.Overlap = c.MaxTokens / 2
+	}
+	return c
+}
+
+// EstimateTokens approximates the token count of s without a model-specific
+// tokenizer: roughly four ASCII characters per token and one token per
+// non-ASCII rune (Hangul syllables usually encode to at least one token).
+func EstimateTokens(s string) int {
+	ascii, other := 0, 0
+	for _, r := range s {
+		if r < 0x80 {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
+
+// section is a heading together with the blocks up to the next heading.
+type section struct {
+	path   []string
+	blocks []markdown.Block
+}
+
+// Split cuts the Markdown of one document into chunks. Consecutive sections
+// are merged while they fit the budget; a section larger than the budget is
+// split between blocks, tables between rows (repeating the header) and long
+// paragraphs between lines or words. A fenced code block is never split, so a
+// chunk holding a single oversized code block may exceed the budget. nav is
+// the navigation path leading to the document, excluding its title.
+func (c *Chunker) Split(title, url string, nav []string, md string) []Chunk {
+	var (
+		chunks []Chunk
+		cur    []string
+		curTok int
+		path   []string
+	)
+	emit := func(texts []string, crumb []string) {
+		text := strings.TrimSpace(strings.Join(texts, "\n\n"))
+		if text == "" {
+			return
+		}
+		chunks = append(chunks, Chunk{
+			ID:         fmt.Sprintf("%s#chunk-%d", url, len(chunks)),
+			Index:      len(chunks),
+			Title:      title,
+			URL:        url,
+			Breadcrumb: breadcrumb(nav, title, crumb),
+			Text:       text,
+			Tokens:     c.Count(text),
+		})
+	}
+	flush := func() {
+		emit(cur, path)
+		cur, curTok, path = nil, 0, nil
+	}
+
+	for _, s := range sections(md) {
+		var texts []string
+		for _, b := range s.blocks {
+			texts = append(texts, b.Text())
+		}
+		text := strings.Join(texts, "\n\n")
+		tok := c.Count(text)
+		if len(cur) > 0 && curTok+tok <= c.MaxTokens {
+			cur = append(cur, text)
+			curTok += tok
+			path = commonPrefix(path, s.path)
+			continue
+		}
+		flush()
+		if tok <= c.MaxTokens {
+			cur, curTok, path = []string{text}, tok, s.path
+			continue
+		}
+		for _, part := range c.pack(c.units(s.blocks)) {
+			emit(part, s.path)
+		}
+	}
+	flush()
+	return chunks
+}
+
+// units breaks a section's blocks into pieces that each fit the budget, except
+// for code blocks which are kept whole. A heading is glued to the piece that
+// follows it.
+func (c *Chunker) units(blocks []markdown.Block) []string {
+	var (
+		out     []string
+		pending string
+	)
+	add := func(s string) {
+		if pending != "" {
+			s = pending + "\n\n" + s
+			pending = ""
+		}
+		out = append(out, s)
+	}
+	for _, b := range blocks {
+		switch {
+		case b.Kind == markdown.Heading:
+			if pending != "" {
+				pending += "\n\n"
+			}
+			pending += b.Text()
+		case b.Kind == markdown.Code || c.Count(b.Text()) <= c.MaxTokens:
+			add(b.Text())
+		case b.Kind == markdown.Table:
+			for _, rows := range c.splitTable(b.Lines) {
+				add(rows)
+			}
+		default:
+			for _, p := range c.splitText(b.Lines) {
+				add(p)
+			}
+		}
+	}
+	if pending != "" {
+		out = append(out, pending)
+	}
+	return out
+}
+
+// pack greedily groups units into chunks under the budget, starting each
+// chunk after the first with up to Overlap tokens taken from the end of the
+// previous one.
+func (c *Chunker) pack(units []string) [][]string {
+	var (
+		out    [][]string
+		cur    []string
+		curTok int
+	)
+	for _, u := range units {
+		tok := c.Count(u)
+		if len(cur) > 0 && curTok+tok > c.MaxTokens {
+			out = append(out, cur)
+			cur, curTok = nil, 0
+			if o := c.overlap(out[len(out)-1]); o != "" && c.Count(o)+tok <= c.MaxTokens {
+				cur, curTok = []string{o}, c.Count(o)
+			}
+		}
+		cur = append(cur, u)
+		curTok += tok
+	}
+	if len(cur) > 0 {
+		out = append(out, cur)
+	}
+	return out
+}
+
+// overlap returns trailing text of prev worth at most Overlap tokens. Only
+// prose is repeated; a trailing table or code block yields no overlap.
+func (c *Chunker) overlap(prev []string) string {
+	if c.Overlap == 0 || len(prev) == 0 {
+		return ""
+	}
+	last := prev[len(prev)-1]
+	lines := strings.Split(strings.TrimSpace(last), "\n")
+	if t := strings.TrimSpace(lines[len(lines)-1]); strings.HasPrefix(t, "|") || markdown.IsFence(t) {
+		return ""
+	}
+	words := strings.Fields(last)
+	i := len(words)
+	for i > 0 && c.Count(strings.Join(words[i-1:], " ")) <= c.Overlap {
+		i--
+	}
+	return strings.Join(words[i:], " ")
+}
+
+// splitTable splits table rows into groups under the budget, repeating the
+// header row and delimiter row at the top of every group.
+func (c *Chunker) splitTable(rows []string) []string {
+	var header []string
+	if len(rows) >= 2 && isDelimiterRow(rows[1]) {
+		header, rows = rows[:2], rows[2:]
+	}
+	var (
+		out   []string
+		group []string
+	)
+	headerTok := c.Count(strings.Join(header, "\n"))
+	tok := headerTok
+	for _, r := range rows {
+		rt := c.Count(r) + 1
+		if len(group) > 0 && tok+rt > c.MaxTokens {
+			out = append(out, strings.Join(append(append([]string{}, header...), group...), "\n"))
+			group, tok = nil, headerTok
+		}
+		group = append(group, r)
+		tok += rt
+	}
+	if len(group) > 0 || len(out) == 0 {
+		out = append(out, strings.Join(append(append([]string{}, header...), group...), "\n"))
+	}
+	return out
+}
+
+// splitText splits a paragraph between lines, and an overlong line between
+// words, so that every piece fits the budget.
+func (c *Chunker) splitText(lines []string) []string {
+	var pieces []string
+	for _, l := range lines {
+		if c.Count(l) <= c.MaxTokens {
+			pieces = append(pieces, l)
+			continue
+		}
+		var cur []string
+		for _, w := range strings.Fields(l) {
+			if len(cur) > 0 && c.Count(strings.Join(append(cur, w), " ")) > c.MaxTokens {
+				pieces = append(pieces, strings.Join(cur, " "))
+				cur = nil
+			}
+			cur = append(cur, w)
+		}
+		if len(cur) > 0 {
+			pieces = append(pieces, strings.Join(cur, " "))
+		}
+	}
+	var (
+		out []string
+		cur []string
+	)
+	for _, p := range pieces {
+		if len(cur) > 0 && c.Count(strings.Join(append(cur, p), "\n")) > c.MaxTokens {
+			out = append(out, strings.Join(cur, "\n"))
+			cur = nil
+		}
+		cur = append(cur, p)
+	}
+	if len(cur) > 0 {
+		out = append(out, strings.Join(cur, "\n"))
+	}
+	return out
+}
+
+func isDelimiterRow(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "|") && strings.Trim(s, "|-: ") == "" && strings.Contains(s, "-")
+}
+
+// sections groups blocks by heading, tracking the heading path of each.
+func sections(md string) []section {
+	var (
+		out   []section
+		stack []markdown.Block
+	)
+	for _, b := range markdown.Parse(md) {
+		if b.Kind == markdown.Heading {
+			for len(stack) > 0 && stack[len(stack)-1].Level >= b.Level {
+				stack = stack[:len(stack)-1]
+			}
+			stack = append(stack, b)
+			path := make([]string, len(stack))
+			for i, h := range stack {
+				path[i] = h.Title
+			}
+			out = append(out, section{path: path, blocks: []markdown.Block{b}})
+			continue
+		}
+		if len(out) == 0 {
+			out = append(out, section{})
+		}
+		out[len(out)-1].blocks = append(out[len(out)-1].blocks, b)
+	}
+	return out
+}
+
+// breadcrumb prefixes the heading path with the navigation path and the
+// document title, unless the document's first heading already repeats it.
+func breadcrumb(nav []string, title string, path []string) []string {
+	crumb := make([]string, 0, len(nav)+len(path)+1)
+	crumb = append(crumb, nav...)
+	if title != "" && (len(path) == 0 || path[0] != title) {
+		crumb = append(crumb, title)
+	}
+	return append(crumb, path...)
+}
+
+func commonPrefix(a, b []string) []string {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n:n]
+}
+
+// WriteJSONL writes chunks to w, one JSON object per line.
+func WriteJSONL(w io.Writer, chunks []Chunk) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, ch := range chunks {
+		if err := enc.Encode(ch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package chunk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// wordCount counts whitespace-separated words, which keeps budgets in tests
+// easy to reason about.
+func wordCount(s string) int { return len(strings.Fields(s)) }
+
+func TestSplit_MergesSmallSectionsAndTracksBreadcrumb(t *testing.T) {
+	md := "# Workspace\n\nIntro text.\n\n## Searching Workspace\n\nSearch text here."
+	c := NewChunker(WithMaxTokens(100), WithTokenCounter(wordCount))
+	chunks := c.Split("Workspace", "https://example.com/docs?postId=1", []string{"Maker", "Basic Guide"}, md)
+	if len(chunks) != 1 {
+		t.Fatalf("expected 1 chunk, got %d: %+v", len(chunks), chunks)
+	}
+	ch := chunks[0]
+	if ch.Index != 0 || ch.ID != "https://example.com/docs?postId=1#chunk-0" || ch.URL != "https://example.com/docs?postId=1" {
+		t.Fatalf("unexpected chunk identity: %+v", ch)
+	}
+	if got := strings.Join(ch.Breadcrumb, " > "); got != "Maker > Basic Guide > Workspace" {
+		t.Fatalf("expected breadcrumb to be the nav path and the common prefix, got %q", got)
+	}
+}
+
+func TestSplit_SplitsOnHeadingBoundaries(t *testing.T) {
+	body := strings.Repeat("word ", 30)
+	md := "# Doc\n\n## A\n\n" + body + "\n\n## B\n\n" + body
+	c := NewChunker(WithMaxTokens(40), WithTokenCounter(wordCount))
+	chunks := c.Split("Doc", "u", nil, md)
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	if got := strings.Join(chunks[1].Breadcrumb, " > "); got != "Doc > B" {
+		t.Fatalf("unexpected breadcrumb %q", got)
+	}
+	if !strings.HasPrefix(chunks[1].Text, "## B") {
+		t.Fatalf("second chunk should start at heading B, got %q", chunks[1].Text[:10])
+	}
+}
+
+func TestSplit_NeverSplitsCodeOrTableRows(t *testing.T) {
+	var rows []string
+	for i := 0; i < 20; i++ {
+		rows = append(rows, fmt.Sprintf("| row %d has four words |", i))
+	}
+	table := "| h |\n| --- |\n" + strings.Join(rows, "\n")
+	code := "```\n" + strings.Repeat("local x = 1\n", 30) + "```"
+	md := "# Doc\n\n" + table + "\n\n" + code
+	c := NewChunker(WithMaxTokens(30), WithTokenCounter(wordCount))
+	chunks := c.Split("Doc", "u", nil, md)
+
+	codeChunks := 0
+	for _, ch := range chunks {
+		if strings.Contains(ch.Text, "```") {
+			codeChunks++
+			if strings.Count(ch.Text, "```") != 2 {
+				t.Fatalf("code fence split across chunks: %q", ch.Text)
+			}
+		}
+		for _, line := range strings.Split(ch.Text, "\n") {
+			if strings.HasPrefix(line, "| row") && !strings.HasSuffix(line, "|") {
+				t.Fatalf("table row split: %q", line)
+			}
+		}
+		if strings.Contains(ch.Text, "| row") && !strings.Contains(ch.Text, "| h |\n| --- |") {
+			t.Fatalf("table continuation should repeat header: %q", ch.Text)
+		}
+	}
+	if codeChunks != 1 {
+		t.Fatalf("expected the code block in exactly one chunk, got %d", codeChunks)
+	}
+}
+
+func TestSplit_OverlapRepeatsTrailingProse(t *testing.T) {
+	var lines []string
+	for i := 0; i < 6; i++ {
+		lines = append(lines, fmt.Sprintf("sentence number %d ends here", i))
+	}
+	md := "# Doc\n\n" + strings.Join(lines, "\n\n")
+	c := NewChunker(WithMaxTokens(12), WithOverlap(3), WithTokenCounter(wordCount))
+	chunks := c.Split("Doc", "u", nil, md)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	if !strings.HasPrefix(chunks[1].Text, "1 ends here") {
+		t.Fatalf("expected overlap from previous chunk, got %q", chunks[1].Text)
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	chunks := NewChunker().Split("T", "https://example.com/?a=1&b=2", nil, "# T\n\nbody")
+	if err := WriteJSONL(&buf, chunks); err != nil {
+		t.Fatalf("WriteJSONL: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(chunks) {
+		t.Fatalf("expected %d lines, got %d", len(chunks), len(lines))
+	}
+	var got Chunk
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got.URL != "https://example.com/?a=1&b=2" || got.Text != "# T\n\nbody" {
+		t.Fatalf("unexpected chunk: %+v", got)
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	if got := EstimateTokens("abcdefgh"); got != 2 {
+		t.Fatalf("ascii: want 2 got %d", got)
+	}
+	if got := EstimateTokens("워크스페이스"); got != 6 {
+		t.Fatalf("hangul: want 6 got %d", got)
+	}
+}
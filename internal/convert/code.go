@@ -0,0 +1,180 @@
+package convert
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// codeClasses lists class names the documentation editor puts on code
+// containers that are not <pre> elements. Highlighters put some of them on
+// inline elements too, so they mark a code block only on a <div>.
+var codeClasses = []string{"ql-syntax", "ql-code-block-container", "code-block", "hljs", "highlight"}
+
+// TagCodeBlocks normalises every code container in the HTML fragment to
+// <pre><code class="language-x">, so mdream emits a fenced block tagged with
+// the language and keeps the code's indentation. Containers are <pre>
+// elements and <div> elements carrying one of the editor's code classes; line
+// breaks written as <br> or as one block element per line become newlines.
+// The language comes from a language-x/lang-x class when present and from
+// DetectLanguage otherwise.
+func TagCodeBlocks(fragment string) (string, error) {
+	root, err := parseFragment(fragment)
+	if err != nil {
+		return "", err
+	}
+
+	var blocks []*html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if isCodeContainer(n) {
+			// Nested containers are part of the outer block
+			blocks = append(blocks, n)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	for _, n := range blocks {
+		code := strings.Trim(rawText(n), "\n")
+		lang := classLanguage(n)
+		if lang == "" {
+			lang = DetectLanguage(code)
+		}
+		codeEl := &html.Node{Type: html.ElementNode, DataAtom: atom.Code, Data: "code"}
+		if lang != "" {
+			codeEl.Attr = []html.Attribute{{Key: "class", Val: "language-" + lang}}
+		}
+		codeEl.AppendChild(&html.Node{Type: html.TextNode, Data: code})
+		pre := &html.Node{Type: html.ElementNode, DataAtom: atom.Pre, Data: "pre"}
+		pre.AppendChild(codeEl)
+		n.Parent.InsertBefore(pre, n)
+		n.Parent.RemoveChild(n)
+	}
+	return renderFragment(root)
+}
+
+func isCodeContainer(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if n.DataAtom == atom.Pre {
+		return true
+	}
+	if n.DataAtom != atom.Div {
+		return false
+	}
+	for _, c := range strings.Fields(attr(n, "class")) {
+		for _, want := range codeClasses {
+			if c == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// classLanguage returns the language named by a language-x or lang-x class
+// on n or any of its descendants.
+func classLanguage(n *html.Node) string {
+	if n.Type == html.ElementNode {
+		for _, c := range strings.Fields(attr(n, "class")) {
+			for _, prefix := range []string{"language-", "lang-"} {
+				if lang, ok := strings.CutPrefix(c, prefix); ok && lang != "" {
+					return strings.ToLower(lang)
+				}
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if lang := classLanguage(c); lang != "" {
+			return lang
+		}
+	}
+	return ""
+}
+
+// rawText returns the text of n with its whitespace intact. <br> and the end
+// of each block-level element (the editor writes one <div> or <p> per line)
+// become newlines.
+func rawText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(strings.ReplaceAll(n.Data, "\u00a0", " "))
+			return
+		case n.Type == html.ElementNode && n.DataAtom == atom.Br:
+			b.WriteByte('\n')
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Div, atom.P, atom.Li:
+				if s := b.String(); s != "" && !strings.HasSuffix(s, "\n") {
+					b.WriteByte('\n')
+				}
+			}
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// luaSignals are features of Lua and of the mlua scripts MapleStory Worlds
+// uses, with their weight. A sample scoring luaThreshold or more is Lua.
+var luaSignals = []struct {
+	re     *regexp.Regexp
+	weight int
+}{
+	{regexp.MustCompile(`\b_[A-Z]\w*:\w+\s*\(`), 2}, // _EffectService:PlayEffect(
+	{regexp.MustCompile(`(?m)^\s*@(?:Component|Logic|Struct|Item|State|EventSender|Sync|ExecSpace)\b`), 2},
+	{regexp.MustCompile(`\blocal\s+[A-Za-z_]\w*`), 2},
+	{regexp.MustCompile(`\bself[:.]\w+`), 2},
+	{regexp.MustCompile(`(?m)^\s*(?:local\s+)?function\b`), 2},
+	{regexp.MustCompile(`(?m)^\s*script\s+\w+\s+extends\s+\w+`), 2},
+	{regexp.MustCompile(`(?m)^\s*end\s*$`), 1},
+	{regexp.MustCompile(`\bthen\b`), 1},
+	{regexp.MustCompile(`\belseif\b`), 1},
+	{regexp.MustCompile(`~=`), 1},
+	{regexp.MustCompile(`(?m)(?:^|\s)--`), 1},
+	{regexp.MustCompile(`\bnil\b`), 1},
+	{regexp.MustCompile(`\bfor\s+\w+(?:\s*,\s*\w+)*\s+in\b|\bfor\s+\w+\s*=`), 1},
+	{regexp.MustCompile(`\bprint\s*\(`), 1},
+	{regexp.MustCompile(`\b(?:Vector2|Vector3|Color|Quaternion)\s*\(`), 1},
+}
+
+const luaThreshold = 2
+
+// DetectLanguage guesses the language of a code sample: "json" for a JSON
+// object or array, "lua" for Lua and mlua scripts, and "" when there is not
+// enough evidence, such as for plain method signatures.
+func DetectLanguage(code string) string {
+	s := strings.TrimSpace(code)
+	if s == "" {
+		return ""
+	}
+	if (s[0] == '{' || s[0] == '[') && json.Valid([]byte(s)) {
+		return "json"
+	}
+	score := 0
+	for _, sig := range luaSignals {
+		if sig.re.MatchString(s) {
+			score += sig.weight
+			if score >= luaThreshold {
+				return "lua"
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	cases := []struct {
+		name, code, want string
+	}{
+		{"service call", `_EffectService:PlayEffect("abc", self.Entity, Vector3(0, 0, 0), 0, Vector3(1, 1, 1))`, "lua"},
+		{"component", "@Component\nscript NewComponent extends Component\nend", "lua"},
+		{"local function", "local function add(a, b)\n    return a + b\nend", "lua"},
+		{"if block", "if x ~= nil then\n    print(x)\nend", "lua"},
+		{"json object", `{"name": "Slime", "hp": [1, 2]}`, "json"},
+		{"json array", `[1, 2, 3]`, "json"},
+		{"signature", "method void TestMethod(float parameter)", ""},
+		{"prose", "Click the Play button.", ""},
+		{"empty", "  \n", ""},
+	}
+	for _, tc := range cases {
+		if got := DetectLanguage(tc.code); got != tc.want {
+			t.Errorf("%s: DetectLanguage = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestTagCodeBlocks_Pre(t *testing.T) {
+	in := `<p>Example:</p><pre>local x = 1<br>if x then<br>    print(x)<br>end</pre>`
+	out, err := TagCodeBlocks(in)
+	if err != nil {
+		t.Fatalf("TagCodeBlocks: %v", err)
+	}
+	want := "<pre><code class=\"language-lua\">local x = 1\nif x then\n    print(x)\nend</code></pre>"
+	if !strings.Contains(out, want) {
+		t.Fatalf("expected %q in output, got: %s", want, out)
+	}
+	if !strings.Contains(out, "<p>Example:</p>") {
+		t.Fatalf("surrounding content should be kept, got: %s", out)
+	}
+}
+
+func TestTagCodeBlocks_EditorLinesAndClass(t *testing.T) {
+	in := `<div class="ql-code-block-container"><div class="ql-code-block">function OnBeginPlay()</div>` +
+		`<div class="ql-code-block">&nbsp;&nbsp;&nbsp;&nbsp;self.Enable = true</div><div class="ql-code-block">end</div></div>` +
+		`<pre class="ql-syntax"><code class="language-JSON">{"a": 1}</code></pre>`
+	out, err := TagCodeBlocks(in)
+	if err != nil {
+		t.Fatalf("TagCodeBlocks: %v", err)
+	}
+	for _, want := range []string{
+		"<pre><code class=\"language-lua\">function OnBeginPlay()\n    self.Enable = true\nend</code></pre>",
+		"<pre><code class=\"language-json\">{&#34;a&#34;: 1}</code></pre>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "ql-code-block") {
+		t.Fatalf("editor containers should be replaced, got: %s", out)
+	}
+}
+
+func TestTagCodeBlocks_InlineHighlightStaysInline(t *testing.T) {
+	in := `<p>Call <code class="hljs">SetEnable(true)</code> on the <span class="highlight">entity</span> first.</p>`
+	out, err := TagCodeBlocks(in)
+	if err != nil {
+		t.Fatalf("TagCodeBlocks: %v", err)
+	}
+	if out != in {
+		t.Fatalf("inline highlighted code must stay inline, got: %s", out)
+	}
+}
+
+func TestTagCodeBlocks_Unknown(t *testing.T) {
+	out, err := TagCodeBlocks(`<pre>Property number NewProperty = 5</pre>`)
+	if err != nil {
+		t.Fatalf("TagCodeBlocks: %v", err)
+	}
+	if want := "<pre><code>Property number NewProperty = 5</code></pre>"; out != want {
+		t.Fatalf("got %q, want %q", out, want)
+	}
+}
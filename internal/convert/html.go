@@ -0,0 +1,76 @@
+// Package convert prepares crawled HTML fragments for Markdown conversion.
+// The Markdown itself is produced by mdream; this package rewrites the parts of
+// the HTML that mdream would otherwise turn into noise for text-only readers.
+// Sanitize also makes fragments safe to serve as HTML again.
+package convert
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// parseFragment parses an HTML fragment and returns a synthetic <body> element
+// holding the parsed nodes, so that top-level nodes can be edited in place.
+func parseFragment(s string) (*html.Node, error) {
+	body := &html.Node{Type: html.ElementNode, DataAtom: atom.Body, Data: "body"}
+	nodes, err := html.ParseFragment(strings.NewReader(s), body)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		body.AppendChild(n)
+	}
+	return body, nil
+}
+
+// renderFragment serialises the children of root back to HTML.
+func renderFragment(root *html.Node) (string, error) {
+	var buf bytes.Buffer
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&buf, c); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+// getAttr returns the value of the named attribute, if present.
+func getAttr(n *html.Node, name string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && strings.EqualFold(a.Key, name) {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// attr returns the value of the named attribute or "".
+func attr(n *html.Node, name string) string {
+	v, _ := getAttr(n, name)
+	return v
+}
+
+// textContent returns the whitespace-collapsed text of n and its descendants.
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return collapseSpace(b.String())
+}
+
+// collapseSpace trims s and folds runs of whitespace into single spaces.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,260 @@
+package convert
+
+import (
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// genericAlts lists alt texts that carry no information about the image.
+var genericAlts = map[string]bool{
+	"":        true,
+	"custom":  true,
+	"image":   true,
+	"img":     true,
+	"picture": true,
+	"photo":   true,
+	"icon":    true,
+}
+
+// RewriteImages replaces every <img> in the HTML fragment with a textual
+// placeholder so that text-only output keeps what the image conveys:
+//   - shields.io badges become inline tags such as "[ReadOnly]" or "[Target: Lv.1]"
+//   - decorative images (empty alt, role="presentation", aria-hidden, tiny icons) are dropped
+//   - other images become "[Image: <alt>; caption: <figcaption>; context: <nearest heading>]"
+//
+// Figure captions folded into a placeholder are removed so they are not repeated.
+func RewriteImages(fragment string) (string, error) {
+	root, err := parseFragment(fragment)
+	if err != nil {
+		return "", err
+	}
+	imgs, headings := collectImages(root)
+
+	consumed := make(map[*html.Node]bool)
+	for _, img := range imgs {
+		text := imagePlaceholder(img, headings[img], consumed)
+		if text != "" {
+			img.Parent.InsertBefore(&html.Node{Type: html.TextNode, Data: text}, img)
+		}
+		img.Parent.RemoveChild(img)
+	}
+	for caption := range consumed {
+		if caption.Parent != nil {
+			caption.Parent.RemoveChild(caption)
+		}
+	}
+	return renderFragment(root)
+}
+
+// Image is a content image of a fragment: its source and the placeholder
+// text RewriteImages replaces it with.
+type Image struct {
+	// Src is the image URL, resolved against the page.
+	Src         string
+	Placeholder string
+}
+
+// Images returns the images of fragment that RewriteImages turns into
+// "[Image: ...]" placeholders, in document order, so a publication can put
+// the pictures back. Badges and decorative images are left out.
+func Images(fragment, base string) ([]Image, error) {
+	root, err := parseFragment(fragment)
+	if err != nil {
+		return nil, err
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	imgs, headings := collectImages(root)
+	consumed := make(map[*html.Node]bool)
+	var out []Image
+	for _, img := range imgs {
+		src := strings.TrimSpace(attr(img, "src"))
+		text := imagePlaceholder(img, headings[img], consumed)
+		if src == "" || !strings.HasPrefix(text, "[Image") {
+			continue
+		}
+		if u, err := baseURL.Parse(src); err == nil {
+			src = u.String()
+		}
+		out = append(out, Image{Src: src, Placeholder: text})
+	}
+	return out, nil
+}
+
+// collectImages returns the <img> elements under root in document order and
+// the text of the heading preceding each.
+func collectImages(root *html.Node) ([]*html.Node, map[*html.Node]string) {
+	var (
+		imgs     []*html.Node
+		headings = make(map[*html.Node]string)
+		heading  string
+	)
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				if t := textContent(n); t != "" {
+					heading = t
+				}
+				// Images inside headings still belong to the previous section.
+			case atom.Img:
+				imgs = append(imgs, n)
+				headings[n] = heading
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	return imgs, headings
+}
+
+// imagePlaceholder returns the text that replaces img, or "" when the image is
+// decorative. Figure captions used for the text are recorded in consumed.
+func imagePlaceholder(img *html.Node, heading string, consumed map[*html.Node]bool) string {
+	src := attr(img, "src")
+	if tag, ok := badgeTag(src); ok {
+		return tag
+	}
+	if isDecorative(img) {
+		return ""
+	}
+
+	var parts []string
+	if alt := describeAlt(attr(img, "alt"), src); alt != "" {
+		parts = append(parts, alt)
+	} else if title := collapseSpace(attr(img, "title")); title != "" {
+		parts = append(parts, title)
+	}
+	if caption := figureCaption(img); caption != nil {
+		if t := textContent(caption); t != "" {
+			parts = append(parts, "caption: "+t)
+			consumed[caption] = true
+		}
+	}
+	if heading != "" {
+		parts = append(parts, "context: "+heading)
+	}
+	if len(parts) == 0 {
+		return "[Image]"
+	}
+	return "[Image: " + strings.Join(parts, "; ") + "]"
+}
+
+// badgeTag converts a shields.io badge URL into an inline tag. Both the
+// /static/v1?label=..&message=.. and the /badge/<label>-<message>-<color> forms
+// are recognised.
+func badgeTag(src string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(src))
+	if err != nil || !strings.HasSuffix(u.Hostname(), "shields.io") {
+		return "", false
+	}
+	var label, message string
+	if strings.HasPrefix(u.Path, "/badge/") {
+		segs := splitBadgePath(strings.TrimPrefix(u.Path, "/badge/"))
+		switch len(segs) {
+		case 0:
+		case 1, 2:
+			message = segs[0]
+		default:
+			label, message = segs[0], segs[1]
+		}
+	} else {
+		q := u.Query()
+		label, message = q.Get("label"), q.Get("message")
+	}
+	label, message = collapseSpace(label), collapseSpace(message)
+	switch {
+	case message == "" && label == "":
+		return "", false
+	case label == "":
+		return "[" + message + "]", true
+	case message == "":
+		return "[" + label + "]", true
+	default:
+		return "[" + label + ": " + message + "]", true
+	}
+}
+
+// splitBadgePath splits a shields.io /badge/ path on single dashes, treating
+// "--" as a literal dash and "_" as a space, as the badge service does.
+func splitBadgePath(p string) []string {
+	p = strings.TrimSuffix(p, path.Ext(p))
+	const dash = "\x00"
+	p = strings.ReplaceAll(p, "--", dash)
+	p = strings.ReplaceAll(p, "__", "\x01")
+	p = strings.ReplaceAll(p, "_", " ")
+	var segs []string
+	for _, s := range strings.Split(p, "-") {
+		s = strings.ReplaceAll(s, dash, "-")
+		s = strings.ReplaceAll(s, "\x01", "_")
+		if unescaped, err := url.PathUnescape(s); err == nil {
+			s = unescaped
+		}
+		segs = append(segs, s)
+	}
+	return segs
+}
+
+// isDecorative reports whether img is explicitly marked as presentational or is
+// a tiny icon whose meaning is carried by the surrounding text.
+func isDecorative(img *html.Node) bool {
+	if alt, ok := getAttr(img, "alt"); ok && strings.TrimSpace(alt) == "" {
+		return true
+	}
+	switch strings.ToLower(attr(img, "role")) {
+	case "presentation", "none":
+		return true
+	}
+	if strings.EqualFold(attr(img, "aria-hidden"), "true") {
+		return true
+	}
+	w, h := attr(img, "width"), attr(img, "height")
+	return isTinyDimension(w) && isTinyDimension(h)
+}
+
+func isTinyDimension(v string) bool {
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(v), "px"))
+	return err == nil && n > 0 && n <= 16
+}
+
+// describeAlt turns an alt attribute into readable text. Generic values and
+// alts that merely repeat the file name are discarded; underscores used as
+// word separators in file-style alts become spaces.
+func describeAlt(alt, src string) string {
+	alt = collapseSpace(alt)
+	if genericAlts[strings.ToLower(alt)] {
+		return ""
+	}
+	if u, err := url.Parse(src); err == nil && alt == path.Base(u.Path) {
+		return ""
+	}
+	if !strings.Contains(alt, " ") {
+		alt = strings.Join(strings.FieldsFunc(alt, func(r rune) bool { return r == '_' }), " ")
+	}
+	return alt
+}
+
+// figureCaption returns the <figcaption> of the nearest enclosing <figure>.
+func figureCaption(n *html.Node) *html.Node {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && p.DataAtom == atom.Figure {
+			for c := p.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode && c.DataAtom == atom.Figcaption {
+					return c
+				}
+			}
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRewriteImages_Badges(t *testing.T) {
+	in := `<table><thead><tr><th>boolean Enable <img src="https://img.shields.io/static/v1?label=&amp;message=ReadOnly&amp;color=orange" alt="custom">` +
+		` <img src="https://img.shields.io/static/v1?label=&amp;message=HideFromInspector&amp;color=purple" alt="custom"></th></tr></thead></table>` +
+		`<p><img src="https://img.shields.io/static/v1?label=Target&amp;message=Lv.1&amp;color=orange" alt="custom"></p>` +
+		`<p><img src="https://img.shields.io/badge/Time-30m-green.svg"></p>`
+	out, err := RewriteImages(in)
+	if err != nil {
+		t.Fatalf("RewriteImages: %v", err)
+	}
+	for _, want := range []string{"boolean Enable [ReadOnly] [HideFromInspector]", "[Target: Lv.1]", "[Time: 30m]"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "<img") || strings.Contains(out, "shields.io") {
+		t.Fatalf("badge images should be removed, got: %s", out)
+	}
+}
+
+func TestRewriteImages_FigureCaptionAndContext(t *testing.T) {
+	in := `<h1>Workspace</h1><h2>Searching Workspace</h2>` +
+		`<figure><img src="https://cdn.example.com/bbs/1634.png" alt="workspace_MyAvatar"><figcaption>The  My Avatar folder</figcaption></figure>`
+	out, err := RewriteImages(in)
+	if err != nil {
+		t.Fatalf("RewriteImages: %v", err)
+	}
+	want := "[Image: workspace MyAvatar; caption: The My Avatar folder; context: Searching Workspace]"
+	if !strings.Contains(out, want) {
+		t.Fatalf("expected %q in output, got: %s", want, out)
+	}
+	if strings.Contains(out, "figcaption") {
+		t.Fatalf("consumed figcaption should be removed, got: %s", out)
+	}
+}
+
+func TestRewriteImages_DecorativeAndGeneric(t *testing.T) {
+	cases := []struct {
+		html string
+		want string
+	}{
+		{`<p>a<img src="x.png" alt="">b</p>`, "<p>ab</p>"},
+		{`<p>a<img src="x.png" role="presentation" alt="logo">b</p>`, "<p>ab</p>"},
+		{`<p>a<img src="x.png" aria-hidden="true">b</p>`, "<p>ab</p>"},
+		{`<p>a<img src="x.png" width="16" height="16" alt="dot">b</p>`, "<p>ab</p>"},
+		{`<p><img src="https://cdn.example.com/a/shot.png" alt="shot.png"></p>`, "<p>[Image]</p>"},
+		{`<p><img src="x.png" alt="custom" title="Scene editor"></p>`, "<p>[Image: Scene editor]</p>"},
+	}
+	for i, c := range cases {
+		got, err := RewriteImages(c.html)
+		if err != nil {
+			t.Fatalf("case %d: RewriteImages: %v", i, err)
+		}
+		if got != c.want {
+			t.Fatalf("case %d: want %q got %q", i, c.want, got)
+		}
+	}
+}
+
+func TestImages(t *testing.T) {
+	in := `<h2>Searching Workspace</h2>` +
+		`<figure><img src="/bbs/1634.png" alt="workspace_MyAvatar"><figcaption>The  My Avatar folder</figcaption></figure>` +
+		`<p><img src="https://img.shields.io/badge/Time-30m-green.svg"> <img src="icon.png" alt=""> <img src="https://cdn.example.com/x.gif"></p>`
+	got, err := Images(in, "https://maplestoryworlds-creators.nexon.com/en/docs?postId=1")
+	if err != nil {
+		t.Fatalf("Images: %v", err)
+	}
+	want := []Image{
+		{Src: "https://maplestoryworlds-creators.nexon.com/bbs/1634.png", Placeholder: "[Image: workspace MyAvatar; caption: The My Avatar folder; context: Searching Workspace]"},
+		{Src: "https://cdn.example.com/x.gif", Placeholder: "[Image: context: Searching Workspace]"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Images = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("image %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	// The placeholders are exactly what RewriteImages writes
+	out, _ := RewriteImages(in)
+	for _, img := range got {
+		if !strings.Contains(out, img.Placeholder) {
+			t.Errorf("placeholder %q not in %s", img.Placeholder, out)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package convert
+
+import (
+	"net/url"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Links returns the absolute http(s) URLs that the anchors in fragment point
+// to, resolved against base, in document order and without duplicates.
+// Same-page fragment links are skipped.
+func Links(fragment, base string) ([]string, error) {
+	root, err := parseFragment(fragment)
+	if err != nil {
+		return nil, err
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []string
+	seen := make(map[string]bool)
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.DataAtom == atom.A {
+			if href, ok := getAttr(n, "href"); ok {
+				if u, err := baseURL.Parse(href); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+					u.Fragment = ""
+					if s := u.String(); s != baseURL.String() && !seen[s] {
+						seen[s] = true
+						links = append(links, s)
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(root)
+	return links, nil
+}
@@ -0,0 +1,24 @@
+package convert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLinks(t *testing.T) {
+	const base = "https://maplestoryworlds-creators.nexon.com/en/docs/?postId=472"
+	in := `<p><a href="#top">Top</a> <a href="/en/docs/?postId=10">A</a>
+<a href="https://example.com/x#frag">B</a> <a href="/en/docs/?postId=10">A again</a>
+<a href="mailto:me@example.com">Mail</a> <a>No href</a></p>`
+	got, err := Links(in, base)
+	if err != nil {
+		t.Fatalf("Links: %v", err)
+	}
+	want := []string{
+		"https://maplestoryworlds-creators.nexon.com/en/docs/?postId=10",
+		"https://example.com/x",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,91 @@
+package convert
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// unsafeElements are removed with their content: they run code, load other
+// documents or submit data.
+var unsafeElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Iframe: true, atom.Frame: true, atom.Frameset: true,
+	atom.Object: true, atom.Embed: true, atom.Applet: true,
+	atom.Form: true, atom.Input: true, atom.Button: true, atom.Textarea: true, atom.Select: true,
+	atom.Link: true, atom.Meta: true, atom.Base: true,
+}
+
+// urlAttrs are the attributes holding a URL, checked against safeURL.
+var urlAttrs = map[string]bool{
+	"href": true, "src": true, "action": true, "formaction": true,
+	"poster": true, "background": true, "cite": true,
+}
+
+// Sanitize makes a crawled HTML fragment safe to serve from another origin:
+// it drops scripting elements, SVG and MathML, event handler, style and
+// srcset attributes, and URLs whose scheme is not http, https or mailto (data URLs
+// are kept for raster images).
+func Sanitize(fragment string) (string, error) {
+	root, err := parseFragment(fragment)
+	if err != nil {
+		return "", err
+	}
+	sanitize(root)
+	return renderFragment(root)
+}
+
+func sanitize(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch {
+		case c.Type == html.CommentNode:
+			n.RemoveChild(c)
+		case c.Type != html.ElementNode:
+		case unsafeElements[c.DataAtom] || c.Namespace != "":
+			n.RemoveChild(c)
+		default:
+			attrs := c.Attr[:0]
+			for _, a := range c.Attr {
+				key := strings.ToLower(a.Key)
+				if a.Namespace != "" || strings.HasPrefix(key, "on") || key == "style" || key == "srcdoc" || key == "srcset" {
+					continue
+				}
+				if urlAttrs[key] && !safeURL(a.Val, key == "src" && c.DataAtom == atom.Img) {
+					continue
+				}
+				attrs = append(attrs, a)
+			}
+			c.Attr = attrs
+			sanitize(c)
+		}
+		c = next
+	}
+}
+
+// safeURL reports whether a URL attribute value may be served: relative URLs
+// and http, https and mailto URLs, plus raster data URLs for images.
+func safeURL(v string, image bool) bool {
+	// Browsers ignore control characters and spaces inside the scheme
+	v = strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, v)
+	lower := strings.ToLower(v)
+	if image && strings.HasPrefix(lower, "data:image/") && !strings.HasPrefix(lower, "data:image/svg") {
+		return true
+	}
+	u, err := url.Parse(v)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	}
+	return false
+}
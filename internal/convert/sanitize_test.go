@@ -0,0 +1,28 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	in := `<div onclick="x()" class="post"><script>alert(1)</script><style>p{}</style>` +
+		`<p style="color:red">Text <a href="java&#10;script:alert(1)">bad</a> <a href="/docs?postId=1" onmouseover="y()">good</a></p>` +
+		`<img src="https://cdn.example.com/a.png" onerror="z()" alt="a"><img src="data:image/png;base64,AAAA">` +
+		`<img src="data:image/svg+xml,<svg onload=alert(1)>"><iframe src="https://evil.example"></iframe>` +
+		`<svg><script>alert(2)</script></svg><form action="/x"><input name="q"></form><!-- note --></div>`
+	got, err := Sanitize(in)
+	if err != nil {
+		t.Fatalf("Sanitize: %v", err)
+	}
+	want := `<div class="post"><p>Text <a>bad</a> <a href="/docs?postId=1">good</a></p>` +
+		`<img src="https://cdn.example.com/a.png" alt="a"/><img src="data:image/png;base64,AAAA"/><img/></div>`
+	if got != want {
+		t.Fatalf("Sanitize =\n%s\nwant\n%s", got, want)
+	}
+	for _, bad := range []string{"script", "alert", "onerror", "iframe", "<svg", "<form"} {
+		if strings.Contains(got, bad) {
+			t.Errorf("sanitized HTML still contains %q", bad)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Package corpus loads the JSON corpora written by the crawl command and
+// offers the lookups shared by the servers: documents by ID, full-text
+// search and the parsed API model.
+package corpus
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/apiref"
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/search"
+)
+
+// URIScheme prefixes document IDs to form the resource URIs handed out to
+// MCP clients, e.g. mswdocs://en/docs/472.
+const URIScheme = "mswdocs://"
+
+// URI returns the resource URI of a document.
+func URI(d document.Document) string { return URIScheme + d.ID }
+
+// IDFromURI returns the document ID addressed by a resource URI.
+func IDFromURI(uri string) (string, bool) {
+	if !strings.HasPrefix(uri, URIScheme) {
+		return "", false
+	}
+	return strings.TrimPrefix(uri, URIScheme), true
+}
+
+// Corpus is an in-memory, read-only view of one or more crawl outputs.
+type Corpus struct {
+	Docs    []document.Document
+	byID    map[string]int
+	index   *search.Index
+	classes map[string]map[string]*apiref.Class // lang -> lower-cased class name -> class
+	enums   map[string][]*apiref.Enum           // lang -> enums in corpus order
+}
+
+// DefaultPaths returns the JSON corpora written by the crawl command under
+// docs/<lang>/.
+func DefaultPaths() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join("docs", "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Load reads and merges the given corpus files.
+func Load(paths ...string) (*Corpus, error) {
+	var docs []document.Document
+	for _, p := range paths {
+		d, err := document.LoadJSON(p)
+		if err != nil {
+			return nil, fmt.Errorf("load %s: %w", p, err)
+		}
+		docs = append(docs, d...)
+	}
+	return New(docs), nil
+}
+
+// New builds a corpus from documents. Documents sharing an ID keep the first
+// occurrence; documents without one get an ID derived from their URL.
+func New(docs []document.Document) *Corpus {
+	c := &Corpus{
+		byID:    make(map[string]int),
+		index:   search.NewIndex(),
+		classes: make(map[string]map[string]*apiref.Class),
+		enums:   make(map[string][]*apiref.Enum),
+	}
+	for _, d := range docs {
+		if d.ID == "" {
+			d.ID, d.Lang, d.Kind = document.IDOf(d.URL), document.LangOf(d.URL), document.KindOf(d.URL)
+		}
+		if _, dup := c.byID[d.ID]; dup {
+			continue
+		}
+		c.byID[d.ID] = len(c.Docs)
+		c.Docs = append(c.Docs, d)
+		c.index.Add(search.Entry{ID: d.ID, Title: d.Title, URL: d.URL, Text: d.Markdown})
+
+		if d.Kind != document.KindAPI {
+			continue
+		}
+		if apiref.IsEnumURL(d.URL) {
+			if e, ok := apiref.ParseEnum(d.URL, d.Markdown); ok {
+				c.enums[d.Lang] = append(c.enums[d.Lang], e)
+			}
+			continue
+		}
+		if cls, ok := apiref.Parse(d.URL, d.Markdown); ok {
+			if c.classes[d.Lang] == nil {
+				c.classes[d.Lang] = make(map[string]*apiref.Class)
+			}
+			c.classes[d.Lang][strings.ToLower(cls.Name)] = cls
+		}
+	}
+	return c
+}
+
+// Doc returns the document with the given ID.
+func (c *Corpus) Doc(id string) (document.Document, bool) {
+	i, ok := c.byID[id]
+	if !ok {
+		return document.Document{}, false
+	}
+	return c.Docs[i], true
+}
+
+// Search runs a BM25 query, optionally restricted to one language, and
+// returns at most limit results (all when limit <= 0).
+func (c *Corpus) Search(query, lang string, limit int) []search.Result {
+	var out []search.Result
+	for _, r := range c.index.Search(query, 0) {
+		if lang != "" {
+			if d, ok := c.Doc(r.ID); !ok || d.Lang != lang {
+				continue
+			}
+		}
+		out = append(out, r)
+		if limit > 0 && len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+// Class looks up an API class by case-insensitive name. An empty lang
+// prefers English and falls back to any language.
+func (c *Corpus) Class(lang, name string) (*apiref.Class, bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if lang != "" {
+		cls, ok := c.classes[lang][key]
+		return cls, ok
+	}
+	if cls, ok := c.classes["en"][key]; ok {
+		return cls, true
+	}
+	for _, l := range c.Langs() {
+		if cls, ok := c.classes[l][key]; ok {
+			return cls, true
+		}
+	}
+	return nil, false
+}
+
+// Classes returns the API classes of a language sorted by name.
+func (c *Corpus) Classes(lang string) []*apiref.Class {
+	out := make([]*apiref.Class, 0, len(c.classes[lang]))
+	for _, cls := range c.classes[lang] {
+		out = append(out, cls)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Enums returns the enums of a language sorted by name.
+func (c *Corpus) Enums(lang string) []*apiref.Enum {
+	out := slices.Clone(c.enums[lang])
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Langs returns the languages present in the corpus, sorted.
+func (c *Corpus) Langs() []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, d := range c.Docs {
+		if !seen[d.Lang] {
+			seen[d.Lang] = true
+			out = append(out, d.Lang)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
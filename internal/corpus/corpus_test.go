@@ -0,0 +1,97 @@
+package corpus
+
+import (
+	"path/filepath"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/document"
+)
+
+const apiPage = "# AIComponent\n\nUses BehaviorTree.\n\n# Properties\n\n| boolean IsLegacy |\n| --- |\n| Legacy flag. |\n"
+
+func sampleDocs() []document.Document {
+	return []document.Document{
+		{Title: "Workspace", URL: "https://maplestoryworlds-creators.nexon.com/en/docs/?postId=472", Markdown: "# Workspace\n\nSearching Workspace"},
+		{Title: "워크스페이스", URL: "https://maplestoryworlds-creators.nexon.com/ko/docs/?postId=472", Markdown: "# 워크스페이스\n\n워크스페이스 검색"},
+		{Title: "AIComponent", URL: "https://maplestoryworlds-creators.nexon.com/en/apiReference/Components/AIComponent", Markdown: apiPage},
+		{Title: "Duplicate", URL: "https://maplestoryworlds-creators.nexon.com/en/docs/?postId=472", Markdown: "dup"},
+	}
+}
+
+func TestNew_IndexesDocsAndClasses(t *testing.T) {
+	c := New(sampleDocs())
+	if len(c.Docs) != 3 {
+		t.Fatalf("expected duplicate ID to be dropped, got %d docs", len(c.Docs))
+	}
+	d, ok := c.Doc("ko/docs/472")
+	if !ok || d.Lang != "ko" || d.Kind != document.KindReference {
+		t.Fatalf("unexpected doc: %+v ok=%v", d, ok)
+	}
+	if got := c.Langs(); len(got) != 2 || got[0] != "en" || got[1] != "ko" {
+		t.Fatalf("unexpected langs: %v", got)
+	}
+
+	res := c.Search("workspace", "en", 5)
+	if len(res) != 1 || res[0].ID != "en/docs/472" {
+		t.Fatalf("unexpected search results: %+v", res)
+	}
+
+	cls, ok := c.Class("", "aicomponent")
+	if !ok || cls.Name != "AIComponent" || len(cls.Properties) != 1 {
+		t.Fatalf("unexpected class lookup: %+v ok=%v", cls, ok)
+	}
+	if _, ok := c.Class("ko", "AIComponent"); ok {
+		t.Fatalf("class should not exist in ko")
+	}
+	if got := c.Classes("en"); len(got) != 1 {
+		t.Fatalf("expected 1 en class, got %d", len(got))
+	}
+}
+
+func TestNew_ParsesEnumPages(t *testing.T) {
+	c := New([]document.Document{{
+		Title:    "UpdateAuthorityType",
+		URL:      "https://maplestoryworlds-creators.nexon.com/en/apiReference/Enums/UpdateAuthorityType",
+		Markdown: "# UpdateAuthorityType\n\n# Members\n\n| Server = 0 |\n| --- |\n| Server side. |\n",
+	}})
+	enums := c.Enums("en")
+	if len(enums) != 1 || enums[0].Name != "UpdateAuthorityType" || len(enums[0].Values) != 1 {
+		t.Fatalf("unexpected enums: %+v", enums)
+	}
+	if len(c.Classes("en")) != 0 {
+		t.Fatalf("enum pages should not be parsed as classes")
+	}
+}
+
+func TestURIRoundTrip(t *testing.T) {
+	d := document.New("Workspace", "https://maplestoryworlds-creators.nexon.com/en/docs/?postId=472", "")
+	uri := URI(d)
+	if uri != "mswdocs://en/docs/472" {
+		t.Fatalf("unexpected URI %q", uri)
+	}
+	if id, ok := IDFromURI(uri); !ok || id != d.ID {
+		t.Fatalf("IDFromURI(%q) = %q, %v", uri, id, ok)
+	}
+	if _, ok := IDFromURI("https://example.com"); ok {
+		t.Fatalf("foreign URI should not resolve")
+	}
+}
+
+func TestLoad_MergesFiles(t *testing.T) {
+	dir := t.TempDir()
+	docs := sampleDocs()
+	a, b := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")
+	if err := document.SaveJSON(a, docs[:2]); err != nil {
+		t.Fatalf("SaveJSON: %v", err)
+	}
+	if err := document.SaveJSON(b, docs[2:3]); err != nil {
+		t.Fatalf("SaveJSON: %v", err)
+	}
+	c, err := Load(a, b)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(c.Docs) != 3 {
+		t.Fatalf("expected 3 docs, got %d", len(c.Docs))
+	}
+}
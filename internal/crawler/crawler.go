@@ -4,14 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"maplestory-world-llms-txt/internal/document"
 	"maplestory-world-llms-txt/internal/logger"
 
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
 )
 
@@ -29,7 +33,18 @@ type Crawler struct {
 	ClickDelay     time.Duration
 	Limit          int
 	OverallTimeout time.Duration
-	Headless       bool
+	// Headless applies to the private browser launched when Session is nil.
+	Headless bool
+	// Session, when set, is the shared browser each run opens a tab in.
+	Session *Session
+	// Limiter, when set, paces navigations and document fetches per host and
+	// backs off when the site answers 429 or 5xx.
+	Limiter *Limiter
+	// Retry governs how content waits are retried; nil uses NewRetryPolicy().
+	Retry *RetryPolicy
+	// DelayJitter spreads ClickDelay log-normally (see HumanDelay); 0 keeps
+	// it constant.
+	DelayJitter float64
 }
 
 // Option configures a Crawler.
@@ -62,6 +77,25 @@ func WithOverallTimeout(d time.Duration) Option {
 // WithHeadless sets whether to run Chrome in headless mode.
 func WithHeadless(b bool) Option { return func(c *Crawler) { c.Headless = b } }
 
+// WithSession makes runs open tabs in s instead of launching their own
+// browser. The caller owns s and closes it.
+func WithSession(s *Session) Option { return func(c *Crawler) { c.Session = s } }
+
+// WithLimiter sets the rate limiter applied to navigations and fetches.
+func WithLimiter(l *Limiter) Option { return func(c *Crawler) { c.Limiter = l } }
+
+// WithDelayJitter randomizes the click delay around ClickDelay. Negative values
+// are clamped to 0 (constant delay).
+func WithDelayJitter(sigma float64) Option {
+	if sigma < 0 {
+		sigma = 0
+	}
+	return func(c *Crawler) { c.DelayJitter = sigma }
+}
+
+// WithRetryPolicy sets the retry policy for content waits.
+func WithRetryPolicy(p *RetryPolicy) Option { return func(c *Crawler) { c.Retry = p } }
+
 // NewCrawler constructs a Crawler using the provided functional options.
 func NewCrawler(opts ...Option) *Crawler {
 	c := &Crawler{}
@@ -73,66 +107,68 @@ func NewCrawler(opts ...Option) *Crawler {
 	return c
 }
 
-// Run crawls the documentation starting at startURL and writes results to outPath
-// using the given format.
-func (c *Crawler) Run(url string) ([]Document, error) {
-	allocOpts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", c.Headless),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("ignore-certificate-errors", true),
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36"),
-	)
+// Run crawls the documentation starting at url. It is RunContext with a
+// background context.
+func (c *Crawler) Run(url string) ([]document.Document, *Report, error) {
+	return c.RunContext(context.Background(), url)
+}
 
-	ctx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), allocOpts...)
-	defer cancelAlloc()
+// RunContext crawls the documentation starting at url until done or until
+// ctx is cancelled. Alongside the documents it returns a Report recording what
+// happened to every navigation target; the report is non-nil even on error.
+// When ctx is cancelled mid-crawl, the documents collected so far are
+// returned together with ctx.Err().
+func (c *Crawler) RunContext(ctx context.Context, url string) ([]document.Document, *Report, error) {
+	var docs []document.Document
+	report, err := c.Stream(ctx, url, func(d document.Document) error {
+		docs = append(docs, d)
+		return nil
+	})
+	return docs, report, err
+}
 
-	ctx, cancel := chromedp.NewContext(ctx)
-	defer cancel()
+// Stream crawls like RunContext but hands each document to fn as soon as it
+// is collected instead of accumulating them. If fn returns an error the crawl
+// stops and Stream returns that error.
+func (c *Crawler) Stream(parent context.Context, url string, fn func(document.Document) error) (*Report, error) {
+	report := &Report{StartURL: url, StartedAt: time.Now().UTC()}
+	started := time.Now()
+	defer func() { report.DurationMS = time.Since(started).Milliseconds() }()
 
-	if c.OverallTimeout > 0 {
-		var toCancel context.CancelFunc
-		ctx, toCancel = context.WithTimeout(ctx, c.OverallTimeout)
-		defer toCancel()
-	}
-
-	// Navigate to start URL
-	if err := chromedp.Run(ctx, chromedp.Navigate(url)); err != nil {
-		return []Document{}, err
-	}
-	if err := waitVisible(ctx, navContainerSel, 30*time.Second); err != nil {
-		return []Document{}, fmt.Errorf("navigation container not visible: %w", err)
+	ctx, closeTab, err := c.openStart(parent, url)
+	if err != nil {
+		return report, err
 	}
+	defer closeTab()
+	host := hostOf(url)
+	delay := NewHumanDelay(c.ClickDelay, c.DelayJitter)
 
 	visited := make(map[string]bool)
-	var docs []Document
-	backoff := NewBackoff(500*time.Millisecond, 20*time.Second, 2.0, 0.2)
-
-	// 1) Expansion phase: click any closed node that has children until none remain.
-	for {
-		_ = scrollMenuToEnd(ctx)
-
-		var nodes []*cdp.Node
-		if err := chromedp.Run(ctx, chromedp.Nodes(navContainerSel+" *", &nodes, chromedp.ByQueryAll)); err != nil {
-			return []Document{}, fmt.Errorf("query nodes: %w", err)
-		}
+	retry := c.Retry
+	if retry == nil {
+		retry = NewRetryPolicy()
+	}
 
-		expanded := false
-		for _, n := range nodes {
-			// Condition: span.inactiveDot.isHavingChildren and NOT .isHavingChildrenAndOpen
-			if strings.EqualFold(n.LocalName, "span") && hasAllClasses(n, "inactiveDot", "isHavingChildren") && !hasClass(n, "isHavingChildrenAndOpen") {
-				_ = chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
-					return dom.ScrollIntoViewIfNeeded().WithNodeID(n.NodeID).Do(ctx)
-				}))
-				if err := chromedp.Run(ctx, chromedp.MouseClickNode(n)); err != nil {
-					continue
+	// 1) Expansion phase: open every node of the navigation tree
+	stats, err := expandTree(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Tree = stats
+	slog.Info("expanded_tree", slog.Int("nodes", stats.Nodes), slog.Int("rounds", stats.Rounds), slog.Int("clicks", stats.Clicks))
+	if nodes, err := snapshotTree(ctx); err != nil {
+		slog.Warn("snapshot_tree", slog.String("error", err.Error()))
+	} else {
+		report.Nav = &NavTree{StartURL: url, Lang: document.LangOf(url), Kind: document.KindOf(url), Nodes: nodes}
+		defer func() {
+			urls := make(map[string]string, len(report.Targets))
+			for _, t := range report.Targets {
+				if t.URL != "" {
+					urls[t.XPath] = t.URL
 				}
-				time.Sleep(c.ClickDelay)
-				expanded = true
 			}
-		}
-		if !expanded {
-			break
-		}
+			resolveURLs(report.Nav.Nodes, urls)
+		}()
 	}
 
 	// 2) Phase A - Collect clickable target elements' Full XPaths based on existing conditions
@@ -140,11 +176,13 @@ func (c *Crawler) Run(url string) ([]Document, error) {
 	// Enumerate leaf nodes and compute an XPath for each node individually.
 	var leafNodes []*cdp.Node
 	if err := chromedp.Run(ctx, chromedp.Nodes(navContainerSel+" div.inactiveDepth", &leafNodes, chromedp.ByQueryAll)); err != nil {
-		return []Document{}, fmt.Errorf("query leaf nodes: %w", err)
+		return report, fmt.Errorf("query leaf nodes: %w", err)
 	}
 
 	seen := make(map[string]struct{})
 	var targets []string
+	// crumbs holds the labels of the nav entries enclosing each target
+	crumbs := make(map[string][]string)
 
 	for i, n := range leafNodes {
 		// Skip active parents
@@ -193,6 +231,7 @@ func (c *Crawler) Run(url string) ([]Document, error) {
 		  return parts.reverse().join('') || '/';
 		})()`, uid)
 		_ = chromedp.Run(ctx, chromedp.Evaluate(js, &xpath))
+		crumb := navBreadcrumb(ctx, uid)
 
 		// Clean up the temporary attribute
 		_ = chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
@@ -212,58 +251,85 @@ func (c *Crawler) Run(url string) ([]Document, error) {
 		}
 		seen[xpath] = struct{}{}
 		targets = append(targets, xpath)
+		crumbs[xpath] = crumb
 	}
 
 	// 3) Phase B - For each collected XPath: revisit, expand, click by XPath, and collect content
 	// Navigate to start URL again
+	if err := c.Limiter.Wait(ctx, host); err != nil {
+		return report, err
+	}
 	if err := chromedp.Run(ctx, chromedp.Navigate(url)); err != nil {
-		return []Document{}, err
+		return report, err
 	}
 	if err := waitVisible(ctx, navContainerSel, 30*time.Second); err != nil {
-		return []Document{}, fmt.Errorf("navigation container not visible: %w", err)
-	}
-	for _, xp := range targets {
-		// Re-run expansion phase so that target element exists in DOM
-		for {
-			_ = scrollMenuToEnd(ctx)
-			var nodes []*cdp.Node
-			if err := chromedp.Run(ctx, chromedp.Nodes(navContainerSel+" *", &nodes, chromedp.ByQueryAll)); err != nil {
-				break
-			}
-			expanded := false
-			for _, n := range nodes {
-				if strings.EqualFold(n.LocalName, "span") && hasAllClasses(n, "inactiveDot", "isHavingChildren") && !hasClass(n, "isHavingChildrenAndOpen") {
-					_ = chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
-						return dom.ScrollIntoViewIfNeeded().WithNodeID(n.NodeID).Do(ctx)
-					}))
-					if err := chromedp.Run(ctx, chromedp.MouseClickNode(n)); err != nil {
-						continue
-					}
-					time.Sleep(c.ClickDelay)
-					expanded = true
-				}
+		return report, fmt.Errorf("navigation container not visible: %w", err)
+	}
+	for i, xp := range targets {
+		if ctx.Err() != nil {
+			// Cancelled or the overall timeout expired; nothing further can be fetched
+			for _, rest := range targets[i:] {
+				report.Targets = append(report.Targets, Target{XPath: rest, Outcome: OutcomeSkipped, Error: ctx.Err().Error()})
 			}
-			if !expanded {
-				break
+			break
+		}
+		target := Target{XPath: xp}
+		targetStart := time.Now()
+		record := func(o Outcome, err error) {
+			target.Outcome = o
+			target.DurationMS = time.Since(targetStart).Milliseconds()
+			if err != nil {
+				target.Error = err.Error()
 			}
+			report.Targets = append(report.Targets, target)
 		}
 
-		// Click target by XPath
+		// Re-run expansion so that the target element exists in the DOM; after
+		// the first pass this only touches nodes that collapsed or re-rendered
+		_, _ = expandTree(ctx)
+
+		// Click target by XPath; the click makes the page fetch the document
+		if err := c.Limiter.Wait(ctx, host); err != nil {
+			record(OutcomeSkipped, err)
+			continue
+		}
 		if err := clickByXPath(ctx, xp); err != nil {
+			record(OutcomeClickFailed, err)
 			continue
 		}
-		time.Sleep(c.ClickDelay)
+		sleep(ctx, delay.Next())
 
 		// Determine document URL
 		var curURL string
-		_ = chromedp.Run(ctx, chromedp.Location(&curURL))
-		if curURL == "" || visited[curURL] {
+		if err := chromedp.Run(ctx, chromedp.Location(&curURL)); err != nil || curURL == "" {
+			if err == nil {
+				err = errors.New("no location after click")
+			}
+			record(OutcomeClickFailed, err)
+			continue
+		}
+		target.URL = curURL
+		if visited[curURL] {
+			record(OutcomeDuplicate, nil)
+			continue
+		}
+
+		if !isAllowedDocURL(curURL) {
+			record(OutcomeOutOfScope, ErrOutOfScope)
+			_ = c.Limiter.Wait(ctx, host)
+			_ = chromedp.Run(ctx, chromedp.Navigate(url))
+			_ = waitVisible(ctx, navContainerSel, 15*time.Second)
 			continue
 		}
 
-		// Collect content (with backoff)
+		// Collect content, retrying transient failures
+		fetchStart := time.Now()
+		track := func(n int) {
+			target.Attempts += n
+			target.Retries += max(n-1, 0)
+		}
 		var title string
-		if err := withRetry(backoff, 5, func() error {
+		n, err := retry.Do(ctx, func(ctx context.Context) error {
 			if err := waitVisible(ctx, contentContainerSel, 30*time.Second); err != nil {
 				return err
 			}
@@ -276,19 +342,17 @@ func (c *Crawler) Run(url string) ([]Document, error) {
 			}
 			title = strings.TrimSpace(t)
 			return nil
-		}); err != nil {
-			continue
-		}
-
-		if !isAllowedDocURL(curURL) {
-			_ = chromedp.Run(ctx, chromedp.Navigate(url))
-			_ = waitVisible(ctx, navContainerSel, 15*time.Second)
+		})
+		track(n)
+		if err != nil {
+			record(OutcomeTimeout, err)
 			continue
 		}
+		target.Title = title
 
 		// Fetch innerHTML from current page (no new context)
 		var innerHTML string
-		_ = withRetry(backoff, 3, func() error {
+		n, err = retry.Do(ctx, func(ctx context.Context) error {
 			if err := chromedp.Run(ctx, chromedp.InnerHTML(contentOuterSel, &innerHTML, chromedp.ByQuery)); err != nil {
 				return err
 			}
@@ -297,17 +361,43 @@ func (c *Crawler) Run(url string) ([]Document, error) {
 			}
 			return nil
 		})
+		track(n)
+		if err != nil {
+			// Leave the page unvisited rather than recording an empty document
+			slog.Warn("skip_doc",
+				slog.String("title", title),
+				slog.String("url", curURL),
+				slog.Int("attempts", target.Attempts),
+				slog.String("error", err.Error()),
+			)
+			record(OutcomeEmpty, err)
+			continue
+		}
 
-		doc := Document{Title: title, URL: curURL, InnerHTML: innerHTML, Content: ""}
-		docs = append(docs, doc)
+		doc := document.New(title, curURL, innerHTML)
+		doc.Breadcrumb = crumbs[xp]
+		doc.Fetch = document.Fetch{
+			FetchedAt:  fetchStart.UTC(),
+			DurationMS: time.Since(fetchStart).Milliseconds(),
+			Attempts:   target.Attempts,
+		}
 		visited[curURL] = true
 		logger.LogParsedDoc(nil, doc.Title, doc.URL)
+		record(OutcomeFetched, nil)
+		if err := fn(doc); err != nil {
+			return report, err
+		}
 
 		if c.Limit > 0 && len(visited) >= c.Limit {
+			for _, rest := range targets[i+1:] {
+				report.Targets = append(report.Targets, Target{XPath: rest, Outcome: OutcomeSkipped})
+			}
 			break
 		}
 	}
-	return docs, nil
+	// Only the caller's cancellation is an error; the overall timeout ends the
+	// crawl normally with the documents collected so far
+	return report, parent.Err()
 }
 func waitVisible(ctx context.Context, sel string, timeout time.Duration) error {
 	c, cancel := context.WithTimeout(ctx, timeout)
@@ -346,16 +436,97 @@ func scrollMenuToEnd(ctx context.Context) error {
 	return nil
 }
 
-func withRetry(b *Backoff, maxTries int, fn func() error) error {
-	b.Reset()
-	var err error
-	for i := 0; i < maxTries; i++ {
-		if err = fn(); err == nil {
-			return nil
+// openStart opens a tab (in c.Session, or in a private browser when there is
+// none), applies the overall timeout and navigates to url, waiting for the
+// navigation tree to appear. The returned function releases everything.
+func (c *Crawler) openStart(parent context.Context, url string) (context.Context, func(), error) {
+	var cleanups []func()
+	cleanup := func() {
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
 		}
-		time.Sleep(b.Next())
 	}
-	return err
+
+	sess := c.Session
+	if sess == nil {
+		sess = NewSession(WithSessionHeadless(c.Headless))
+		cleanups = append(cleanups, func() { _ = sess.Close() })
+	}
+	if err := sess.Open(parent); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	ctx, cancel, err := sess.NewTab(parent)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	cleanups = append(cleanups, cancel)
+
+	if c.OverallTimeout > 0 {
+		var toCancel context.CancelFunc
+		ctx, toCancel = context.WithTimeout(ctx, c.OverallTimeout)
+		cleanups = append(cleanups, toCancel)
+	}
+	if c.Limiter != nil {
+		chromedp.ListenTarget(ctx, c.observeResponses)
+	}
+
+	// Navigate to start URL
+	if err := c.Limiter.Wait(ctx, hostOf(url)); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if err := chromedp.Run(ctx, chromedp.Navigate(url)); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if err := waitVisible(ctx, navContainerSel, 30*time.Second); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("navigation container not visible: %w", err)
+	}
+	return ctx, cleanup, nil
+}
+
+// observeResponses feeds the status of document and XHR/fetch responses to
+// the limiter so that 429 and 5xx answers pause further requests to the host.
+func (c *Crawler) observeResponses(ev any) {
+	e, ok := ev.(*network.EventResponseReceived)
+	if !ok || e.Response == nil {
+		return
+	}
+	switch e.Type {
+	case network.ResourceTypeDocument, network.ResourceTypeXHR, network.ResourceTypeFetch:
+	default:
+		return
+	}
+	var retryAfter time.Duration
+	for k, v := range e.Response.Headers {
+		if s, ok := v.(string); ok && strings.EqualFold(k, "Retry-After") {
+			retryAfter = parseRetryAfter(s, time.Now())
+		}
+	}
+	status := int(e.Response.Status)
+	if pause := c.Limiter.Observe(hostOf(e.Response.URL), status, retryAfter); pause > 0 {
+		slog.Warn("throttled",
+			slog.String("url", e.Response.URL),
+			slog.Int("status", status),
+			slog.Duration("pause", pause),
+		)
+	}
+}
+
+// sleep pauses for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
 }
 
 func isAllowedDocURL(raw string) bool {
@@ -369,17 +540,6 @@ func isAllowedDocURL(raw string) bool {
 	return strings.Contains(u.Path, "/docs") || strings.Contains(u.Path, "/apiReference")
 }
 
-func saveOutput(path, format string, docs []Document) error {
-	switch format {
-	case "json":
-		return SaveJSON(path, docs)
-	case "csv":
-		return SaveCSV(path, docs)
-	default:
-		return fmt.Errorf("unknown format: %s", format)
-	}
-}
-
 // clickByXPath finds an element via the given absolute XPath and clicks it in page context.
 func clickByXPath(ctx context.Context, xpath string) error {
 	if strings.TrimSpace(xpath) == "" {
@@ -405,3 +565,39 @@ func clickByXPath(ctx context.Context, xpath string) error {
 	}
 	return nil
 }
+
+// navBreadcrumbJS lists the labels of the nav entries enclosing the entry
+// marked with a data-crawl-uid, outermost first. An entry's label is its own
+// text without that of nested entries.
+const navBreadcrumbJS = `(() => {
+  const container = document.querySelector(%s);
+  const el = document.querySelector('[data-crawl-uid=' + JSON.stringify(%s) + ']');
+  if (!container || !el) return [];
+  const entrySel = 'div.inactiveDepth';
+  function owner(e){ return e ? e.closest(entrySel) : null; }
+  function label(entry){
+    const parts = [];
+    const walker = document.createTreeWalker(entry, NodeFilter.SHOW_TEXT);
+    for (let t = walker.nextNode(); t; t = walker.nextNode()) {
+      if (owner(t.parentElement) === entry) parts.push(t.nodeValue);
+    }
+    return parts.join(' ').replace(/\s+/g, ' ').trim();
+  }
+  const crumb = [];
+  for (let p = owner(el.parentElement); p && container.contains(p); p = owner(p.parentElement)) {
+    const l = label(p);
+    if (l) crumb.unshift(l);
+  }
+  return crumb;
+})()`
+
+// navBreadcrumb returns the navigation path of the nav entry marked with uid,
+// or nil when it cannot be read.
+func navBreadcrumb(ctx context.Context, uid string) []string {
+	var crumb []string
+	js := fmt.Sprintf(navBreadcrumbJS, strconv.Quote(navContainerSel), strconv.Quote(uid))
+	if err := chromedp.Run(ctx, chromedp.Evaluate(js, &crumb)); err != nil {
+		return nil
+	}
+	return crumb
+}
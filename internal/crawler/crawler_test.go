@@ -0,0 +1,25 @@
+package crawler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSleep_ReturnsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	sleep(ctx, time.Minute)
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("sleep ignored cancellation, took %s", d)
+	}
+}
+
+func TestSleep_Waits(t *testing.T) {
+	start := time.Now()
+	sleep(context.Background(), 20*time.Millisecond)
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Fatalf("sleep returned early after %s", d)
+	}
+}
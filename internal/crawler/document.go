@@ -4,20 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-)
 
-// Document represents a crawled document item.
-type Document struct {
-	Title     string `json:"title"`
-	URL       string `json:"url"`
-	InnerHTML string `json:"innerHTML"`
-	Content   string `json:"content"`
-}
+	"maplestory-world-llms-txt/internal/document"
+)
 
-// SaveDocumentFile writes each Document.InnerHTML into a separate file under outFileDir.
+// SaveDocumentFile writes each Document.HTML into a separate file under outFileDir.
 // Files are named using the slice index: "<index>_doc.html". It returns the full paths
 // of the created files in the same order as docs.
-func SaveDocumentFile(docs []Document, outFileDir string) ([]string, error) {
+func SaveDocumentFile(docs []document.Document, outFileDir string) ([]string, error) {
 	paths := make([]string, 0, len(docs))
 	for i := range docs {
 		name := fmt.Sprintf("%d_doc.html", i)
@@ -26,9 +20,9 @@ func SaveDocumentFile(docs []Document, outFileDir string) ([]string, error) {
 		if err != nil {
 			return nil, fmt.Errorf("open %s: %w", p, err)
 		}
-		if _, err := f.WriteString(docs[i].InnerHTML); err != nil {
+		if _, err := f.WriteString(docs[i].HTML); err != nil {
 			_ = f.Close()
-			return nil, fmt.Errorf("write innerHTML: %w", err)
+			return nil, fmt.Errorf("write html: %w", err)
 		}
 		if err := f.Close(); err != nil {
 			return nil, fmt.Errorf("close file: %w", err)
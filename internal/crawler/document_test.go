@@ -4,13 +4,15 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"maplestory-world-llms-txt/internal/document"
 )
 
 func TestSaveDocumentFile_WritesFilesPerIndexAndReturnsPaths(t *testing.T) {
-	docs := []Document{
-		{InnerHTML: "<div>first</div>"},
-		{InnerHTML: "<p>second</p>"},
-		{InnerHTML: "<span>third</span>"},
+	docs := []document.Document{
+		{HTML: "<div>first</div>"},
+		{HTML: "<p>second</p>"},
+		{HTML: "<span>third</span>"},
 	}
 	dir := t.TempDir()
 	paths, err := SaveDocumentFile(docs, dir)
@@ -34,8 +36,8 @@ func TestSaveDocumentFile_WritesFilesPerIndexAndReturnsPaths(t *testing.T) {
 		if err != nil {
 			t.Fatalf("read file %s: %v", p, err)
 		}
-		if string(b) != docs[i].InnerHTML {
-			t.Fatalf("unexpected content for %s: want %q got %q", p, docs[i].InnerHTML, string(b))
+		if string(b) != docs[i].HTML {
+			t.Fatalf("unexpected content for %s: want %q got %q", p, docs[i].HTML, string(b))
 		}
 	}
 }
@@ -0,0 +1,138 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// toggleSel matches navigation nodes that have children but are closed.
+	toggleSel = "span.inactiveDot.isHavingChildren:not(.isHavingChildrenAndOpen)"
+	// treeNodeSel matches every entry of the navigation tree.
+	treeNodeSel = "div.inactiveDepth"
+
+	// toggleAttempts bounds how often one toggle is clicked before it is
+	// considered broken.
+	toggleAttempts = 3
+	// expandQuiet is how long the tree must go without mutations before the
+	// children of the last clicked toggles count as rendered.
+	expandQuiet = 250 * time.Millisecond
+	// expandSettleTimeout caps a single wait for the tree to settle.
+	expandSettleTimeout = 15 * time.Second
+	// maxExpandRounds guards against a tree that never stops growing.
+	maxExpandRounds = 200
+)
+
+// ExpandStats describes a tree expansion.
+type ExpandStats struct {
+	// Rounds is the number of click-and-settle rounds.
+	Rounds int `json:"rounds"`
+	// Clicks is the number of toggles clicked.
+	Clicks int `json:"clicks"`
+	// Nodes is the size of the fully expanded tree.
+	Nodes int `json:"nodes"`
+	// Unsettled counts rounds whose DOM never went quiet before the timeout.
+	Unsettled int `json:"unsettled,omitempty"`
+}
+
+// clickNewTogglesJS clicks every closed toggle under the container that has
+// not yet used up its attempts and returns how many it clicked. Toggles that
+// opened drop out of the selector, so each round only touches nodes that
+// appeared since the previous one (plus stragglers whose click was lost).
+const clickNewTogglesJS = `(function(sel, toggleSel, maxTries){
+  var root = document.querySelector(sel);
+  if (!root) return -1;
+  var clicked = 0;
+  root.querySelectorAll(toggleSel).forEach(function(el){
+    var tries = parseInt(el.getAttribute('data-crawl-tries') || '0', 10);
+    if (tries >= maxTries) return;
+    el.setAttribute('data-crawl-tries', String(tries + 1));
+    try { el.scrollIntoView({block:'nearest'}); } catch (e) {}
+    el.click();
+    clicked++;
+  });
+  return clicked;
+})(%q, %q, %d)`
+
+// settleJS resolves once the container has seen no DOM mutations for quietMs,
+// or with settled=false after timeoutMs.
+const settleJS = `(function(sel, quietMs, timeoutMs){
+  return new Promise(function(resolve){
+    var root = document.querySelector(sel);
+    if (!root) { resolve({settled:false, mutations:0}); return; }
+    var mutations = 0, quiet, deadline, obs;
+    function finish(settled){
+      clearTimeout(quiet); clearTimeout(deadline); obs.disconnect();
+      resolve({settled:settled, mutations:mutations});
+    }
+    obs = new MutationObserver(function(records){
+      mutations += records.length;
+      clearTimeout(quiet);
+      quiet = setTimeout(function(){ finish(true); }, quietMs);
+    });
+    obs.observe(root, {childList:true, subtree:true, attributes:true, attributeFilter:['class']});
+    quiet = setTimeout(function(){ finish(true); }, quietMs);
+    deadline = setTimeout(function(){ finish(false); }, timeoutMs);
+  });
+})(%q, %d, %d)`
+
+// waitDOMStable waits until the subtree under sel stops mutating for quiet.
+// It reports whether the subtree settled before timeout.
+func waitDOMStable(ctx context.Context, sel string, quiet, timeout time.Duration) (bool, error) {
+	var res struct {
+		Settled   bool `json:"settled"`
+		Mutations int  `json:"mutations"`
+	}
+	js := fmt.Sprintf(settleJS, sel, quiet.Milliseconds(), timeout.Milliseconds())
+	err := chromedp.Run(ctx, chromedp.Evaluate(js, &res, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+		return p.WithAwaitPromise(true)
+	}))
+	return res.Settled, err
+}
+
+// expandTree opens every node of the navigation tree. Each round clicks the
+// toggles that are still closed and then waits for the tree to stop changing,
+// which confirms the children rendered without guessing a delay.
+func expandTree(ctx context.Context) (ExpandStats, error) {
+	var stats ExpandStats
+	for stats.Rounds < maxExpandRounds {
+		_ = scrollMenuToEnd(ctx)
+
+		var clicked int
+		if err := chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf(clickNewTogglesJS, navContainerSel, toggleSel, toggleAttempts), &clicked)); err != nil {
+			return stats, fmt.Errorf("expand tree: %w", err)
+		}
+		if clicked < 0 {
+			return stats, fmt.Errorf("expand tree: navigation container not found")
+		}
+		if clicked == 0 {
+			break
+		}
+		stats.Rounds++
+		stats.Clicks += clicked
+
+		settled, err := waitDOMStable(ctx, navContainerSel, expandQuiet, expandSettleTimeout)
+		if err != nil {
+			return stats, fmt.Errorf("expand tree: %w", err)
+		}
+		if !settled {
+			stats.Unsettled++
+		}
+	}
+
+	if err := chromedp.Run(ctx, chromedp.Evaluate(
+		fmt.Sprintf(`document.querySelectorAll(%q).length`, navContainerSel+" "+treeNodeSel), &stats.Nodes)); err != nil {
+		return stats, fmt.Errorf("count tree nodes: %w", err)
+	}
+	slog.Debug("expanded_tree",
+		slog.Int("rounds", stats.Rounds),
+		slog.Int("clicks", stats.Clicks),
+		slog.Int("nodes", stats.Nodes),
+	)
+	return stats, nil
+}
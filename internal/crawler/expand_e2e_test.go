@@ -0,0 +1,89 @@
+//go:build e2e
+
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// lazyTreePage renders a navigation tree whose children appear some time after
+// their parent is clicked, like the documentation site on a slow connection.
+const lazyTreePage = `<!doctype html><html><body><div id="App"><main><div class="contents_wrap"><div class="tree_view_container"></div></div></main></div>
+<script>
+function node(depth, label) {
+  var d = document.createElement('div');
+  d.className = 'inactiveDepth';
+  var s = document.createElement('span');
+  s.textContent = label;
+  d.appendChild(s);
+  if (depth < 3) {
+    s.className = 'inactiveDot isHavingChildren';
+    s.addEventListener('click', function(){
+      if (s.classList.contains('isHavingChildrenAndOpen')) return;
+      s.classList.add('isHavingChildrenAndOpen');
+      setTimeout(function(){
+        for (var i = 0; i < 2; i++) d.appendChild(node(depth + 1, label + '.' + i));
+      }, 100);
+    });
+  }
+  return d;
+}
+var root = document.querySelector('.tree_view_container');
+root.appendChild(node(0, 'a'));
+root.appendChild(node(0, 'b'));
+</script></body></html>`
+
+func TestExpandTree_LazyChildren_E2E(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(lazyTreePage))
+	}))
+	defer srv.Close()
+
+	s := NewSession()
+	if err := s.Open(context.Background()); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+	ctx, cancel, err := s.NewTab(context.Background())
+	if err != nil {
+		t.Fatalf("NewTab: %v", err)
+	}
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, 30*time.Second)
+	defer cancelTimeout()
+
+	if err := chromedp.Run(ctx, chromedp.Navigate(srv.URL)); err != nil {
+		t.Fatalf("navigate: %v", err)
+	}
+	stats, err := expandTree(ctx)
+	if err != nil {
+		t.Fatalf("expandTree: %v", err)
+	}
+	// Two roots, each a complete binary tree of depth 3: 2 * (1+2+4+8) nodes.
+	if stats.Nodes != 30 || stats.Clicks != 14 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	again, err := expandTree(ctx)
+	if err != nil || again.Clicks != 0 || again.Nodes != 30 {
+		t.Fatalf("second expansion should be a no-op, got %+v, %v", again, err)
+	}
+
+	roots, err := snapshotTree(ctx)
+	if err != nil {
+		t.Fatalf("snapshotTree: %v", err)
+	}
+	tree := &NavTree{Nodes: roots}
+	if nodes, leaves := tree.Count(); len(roots) != 2 || nodes != 30 || leaves != 16 {
+		t.Fatalf("unexpected snapshot: %d roots, %d nodes, %d leaves", len(roots), nodes, leaves)
+	}
+	if n := roots[0].Children[1].Children[0]; n.Label != "a.1.0" || n.Depth != 2 || n.Leaf {
+		t.Fatalf("unexpected node: %+v", n)
+	}
+}
@@ -0,0 +1,214 @@
+package crawler
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limiter paces requests per host with token buckets. Hosts share a default
+// rate unless given their own, and a host that answers 429 or 5xx is paused
+// with exponential backoff until it recovers.
+type Limiter struct {
+	rate     float64 // tokens per second; <= 0 means unlimited
+	burst    int
+	hosts    map[string]hostLimit
+	maxPause time.Duration
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+type hostLimit struct {
+	rate  float64
+	burst int
+}
+
+type bucket struct {
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	failures    int
+}
+
+// LimiterOption configures a Limiter.
+type LimiterOption func(*Limiter)
+
+// WithHostLimit overrides the rate (requests per second) and burst for one
+// host. A non-positive rate leaves the host unlimited.
+func WithHostLimit(host string, rate float64, burst int) LimiterOption {
+	return func(l *Limiter) { l.hosts[strings.ToLower(host)] = hostLimit{rate, max(burst, 1)} }
+}
+
+// WithMaxPause caps the pause applied after repeated 429/5xx responses.
+// Negative values are clamped to 0 (no pause).
+func WithMaxPause(d time.Duration) LimiterOption {
+	if d < 0 {
+		d = 0
+	}
+	return func(l *Limiter) { l.maxPause = d }
+}
+
+// NewLimiter returns a Limiter allowing rate requests per second per host
+// with bursts of up to burst requests. burst < 1 is treated as 1.
+func NewLimiter(rate float64, burst int, opts ...LimiterOption) *Limiter {
+	l := &Limiter{
+		rate:     rate,
+		burst:    max(burst, 1),
+		hosts:    make(map[string]hostLimit),
+		maxPause: 2 * time.Minute,
+		buckets:  make(map[string]*bucket),
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(l)
+		}
+	}
+	return l
+}
+
+func (l *Limiter) bucket(host string) *bucket {
+	host = strings.ToLower(host)
+	b, ok := l.buckets[host]
+	if !ok {
+		rate, burst := l.rate, l.burst
+		if h, ok := l.hosts[host]; ok {
+			rate, burst = h.rate, h.burst
+		}
+		b = &bucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: l.now()}
+		l.buckets[host] = b
+	}
+	return b
+}
+
+// reserve takes a token for host if one is available and otherwise returns
+// how long to wait before trying again.
+func (l *Limiter) reserve(host string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.bucket(host)
+	now := l.now()
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+	if b.rate <= 0 {
+		return 0
+	}
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// Wait blocks until a request to host is allowed or ctx is done.
+func (l *Limiter) Wait(ctx context.Context, host string) error {
+	if l == nil {
+		return ctx.Err()
+	}
+	for {
+		d := l.reserve(host)
+		if d <= 0 {
+			return ctx.Err()
+		}
+		sleep(ctx, d)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// Observe feeds a response status seen for host into the limiter. 429 and 5xx
+// pause the host for twice as long on every consecutive occurrence (starting
+// at one second), or for retryAfter when the server asked for longer; any
+// other status clears the backoff. It returns the pause applied, if any.
+func (l *Limiter) Observe(host string, status int, retryAfter time.Duration) time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.bucket(host)
+	if status != 429 && status < 500 {
+		b.failures = 0
+		return 0
+	}
+	b.failures++
+	pause := time.Second << min(b.failures-1, 16)
+	pause = min(max(pause, retryAfter), l.maxPause)
+	if until := l.now().Add(pause); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+	return pause
+}
+
+// hostOf returns the lower-cased host of a URL, or "" if it has none.
+func hostOf(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP
+// date. It returns 0 when the value is missing or malformed.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if n, err := strconv.Atoi(v); err == nil && n > 0 {
+		return time.Duration(n) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// HumanDelay draws pauses from a log-normal distribution around a median, so
+// most pauses stay close to it while an occasional one is much longer, as
+// with a person reading the page. The result is capped at ten times the median.
+type HumanDelay struct {
+	Median time.Duration
+	// Sigma is the spread of the underlying normal distribution; 0 yields a
+	// constant Median.
+	Sigma float64
+
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// NewHumanDelay returns a HumanDelay. Negative arguments are clamped to 0.
+func NewHumanDelay(median time.Duration, sigma float64) *HumanDelay {
+	return &HumanDelay{
+		Median: max(median, 0),
+		Sigma:  math.Max(sigma, 0),
+		r:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Next returns the next pause.
+func (h *HumanDelay) Next() time.Duration {
+	if h.Sigma == 0 || h.Median <= 0 {
+		return h.Median
+	}
+	h.mu.Lock()
+	n := h.r.NormFloat64()
+	h.mu.Unlock()
+	d := time.Duration(float64(h.Median) * math.Exp(h.Sigma*n))
+	return min(d, 10*h.Median)
+}
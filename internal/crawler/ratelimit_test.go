@@ -0,0 +1,113 @@
+package crawler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a Limiter clock that only advances when told to.
+func fakeClock(l *Limiter) *time.Time {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	return &now
+}
+
+func TestLimiter_TokenBucket(t *testing.T) {
+	l := NewLimiter(2, 2)
+	now := fakeClock(l)
+
+	for i := 0; i < 2; i++ {
+		if d := l.reserve("a.example"); d != 0 {
+			t.Fatalf("burst request %d should pass, got wait %s", i, d)
+		}
+	}
+	if d := l.reserve("a.example"); d != 500*time.Millisecond {
+		t.Fatalf("expected 500ms wait after burst, got %s", d)
+	}
+	if d := l.reserve("b.example"); d != 0 {
+		t.Fatalf("hosts should have independent buckets, got wait %s", d)
+	}
+	*now = now.Add(500 * time.Millisecond)
+	if d := l.reserve("a.example"); d != 0 {
+		t.Fatalf("token should have refilled, got wait %s", d)
+	}
+}
+
+func TestLimiter_HostLimitAndUnlimited(t *testing.T) {
+	l := NewLimiter(0, 1, WithHostLimit("Slow.Example", 1, 1))
+	fakeClock(l)
+	for i := 0; i < 5; i++ {
+		if d := l.reserve("fast.example"); d != 0 {
+			t.Fatalf("rate 0 should be unlimited, got wait %s", d)
+		}
+	}
+	l.reserve("slow.example")
+	if d := l.reserve("slow.example"); d != time.Second {
+		t.Fatalf("expected host override to apply, got wait %s", d)
+	}
+}
+
+func TestLimiter_ObserveBacksOff(t *testing.T) {
+	l := NewLimiter(0, 1, WithMaxPause(5*time.Second))
+	now := fakeClock(l)
+
+	if p := l.Observe("h", 200, 0); p != 0 {
+		t.Fatalf("200 should not pause, got %s", p)
+	}
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if p := l.Observe("h", 503, 0); p != want {
+			t.Fatalf("failure %d: pause %s, want %s", i+1, p, want)
+		}
+	}
+	if d := l.reserve("h"); d != 5*time.Second {
+		t.Fatalf("host should be paused, got wait %s", d)
+	}
+	l.Observe("h", 200, 0)
+	if p := l.Observe("h", 429, 3*time.Second); p != 3*time.Second {
+		t.Fatalf("Retry-After should extend the first pause, got %s", p)
+	}
+	*now = now.Add(5 * time.Second)
+	if d := l.reserve("h"); d != 0 {
+		t.Fatalf("pause should have expired, got wait %s", d)
+	}
+}
+
+func TestLimiter_WaitHonoursContext(t *testing.T) {
+	l := NewLimiter(0.001, 1)
+	_ = l.Wait(context.Background(), "h")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, "h"); err == nil {
+		t.Fatalf("expected context error while waiting for a token")
+	}
+	var nilLimiter *Limiter
+	if err := nilLimiter.Wait(context.Background(), "h"); err != nil {
+		t.Fatalf("nil limiter should not block: %v", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if d := parseRetryAfter("120", now); d != 2*time.Minute {
+		t.Fatalf("seconds form: %s", d)
+	}
+	if d := parseRetryAfter("Wed, 01 Jan 2025 00:00:30 GMT", now); d != 30*time.Second {
+		t.Fatalf("date form: %s", d)
+	}
+	if d := parseRetryAfter("soon", now); d != 0 {
+		t.Fatalf("malformed: %s", d)
+	}
+}
+
+func TestHumanDelay(t *testing.T) {
+	if d := NewHumanDelay(100*time.Millisecond, 0).Next(); d != 100*time.Millisecond {
+		t.Fatalf("sigma 0 should be constant, got %s", d)
+	}
+	h := NewHumanDelay(100*time.Millisecond, 1)
+	for i := 0; i < 1000; i++ {
+		if d := h.Next(); d <= 0 || d > time.Second {
+			t.Fatalf("delay %s out of (0, 10x median]", d)
+		}
+	}
+}
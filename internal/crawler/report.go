@@ -0,0 +1,128 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Outcome classifies what happened to a crawl target.
+type Outcome string
+
+const (
+	// OutcomeFetched means the document was collected.
+	OutcomeFetched Outcome = "fetched"
+	// OutcomeDuplicate means the target led to a page that was already collected.
+	OutcomeDuplicate Outcome = "duplicate"
+	// OutcomeClickFailed means the navigation entry could not be found or clicked.
+	OutcomeClickFailed Outcome = "click-failed"
+	// OutcomeTimeout means the content or title never became visible.
+	OutcomeTimeout Outcome = "timeout"
+	// OutcomeOutOfScope means the target led outside the documentation site.
+	OutcomeOutOfScope Outcome = "out-of-scope"
+	// OutcomeEmpty means the page loaded but its content stayed empty.
+	OutcomeEmpty Outcome = "empty"
+	// OutcomeSkipped means the run stopped (limit or timeout) before the target.
+	OutcomeSkipped Outcome = "skipped"
+)
+
+// outcomes lists every Outcome in the order summaries print them.
+var outcomes = []Outcome{
+	OutcomeFetched, OutcomeDuplicate, OutcomeClickFailed, OutcomeTimeout,
+	OutcomeOutOfScope, OutcomeEmpty, OutcomeSkipped,
+}
+
+// Target records how one navigation entry, identified by its XPath, was handled.
+type Target struct {
+	XPath    string  `json:"xpath"`
+	Outcome  Outcome `json:"outcome"`
+	URL      string  `json:"url,omitempty"`
+	Title    string  `json:"title,omitempty"`
+	Attempts int     `json:"attempts,omitempty"`
+	// Retries counts attempts beyond the first of each retried operation.
+	Retries    int    `json:"retries,omitempty"`
+	DurationMS int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Report describes a single Run: when it ran and what happened to every target.
+type Report struct {
+	StartURL   string    `json:"startUrl"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMS int64     `json:"durationMs"`
+	// Tree describes the expansion of the navigation tree.
+	Tree ExpandStats `json:"tree"`
+	// Nav is the navigation tree, with URLs resolved from the targets.
+	Nav     *NavTree `json:"nav,omitempty"`
+	Targets []Target `json:"targets"`
+}
+
+// Counts returns the number of targets per outcome.
+func (r *Report) Counts() map[Outcome]int {
+	m := make(map[Outcome]int)
+	for _, t := range r.Targets {
+		m[t.Outcome]++
+	}
+	return m
+}
+
+// Retries returns the total number of retries across all targets.
+func (r *Report) Retries() int {
+	n := 0
+	for _, t := range r.Targets {
+		n += t.Retries
+	}
+	return n
+}
+
+// Failures returns the targets that were neither fetched, duplicates nor skipped.
+func (r *Report) Failures() []Target {
+	var out []Target
+	for _, t := range r.Targets {
+		switch t.Outcome {
+		case OutcomeFetched, OutcomeDuplicate, OutcomeSkipped:
+		default:
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// Summary returns a one-line summary such as
+// "94 targets in 3m2s: 90 fetched, 2 duplicate, 2 timeout (7 retries)".
+func (r *Report) Summary() string {
+	counts := r.Counts()
+	parts := make([]string, 0, len(outcomes))
+	for _, o := range outcomes {
+		if n := counts[o]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, o))
+		}
+	}
+	d := (time.Duration(r.DurationMS) * time.Millisecond).Round(time.Second)
+	if len(parts) == 0 {
+		return fmt.Sprintf("0 targets in %s", d)
+	}
+	summary := fmt.Sprintf("%d targets in %s: %s", len(r.Targets), d, strings.Join(parts, ", "))
+	if n := r.Retries(); n > 0 {
+		summary += fmt.Sprintf(" (%d retries)", n)
+	}
+	return summary
+}
+
+// SaveReports writes reports as indented JSON to path, creating parent
+// directories as needed.
+func SaveReports(path string, reports []*Report) error {
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	data, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,54 @@
+package crawler
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func sampleReport() *Report {
+	return &Report{
+		StartURL:   "https://maplestoryworlds-creators.nexon.com/en/docs/?postId=472",
+		DurationMS: (90 * time.Second).Milliseconds(),
+		Targets: []Target{
+			{XPath: "/a[1]", Outcome: OutcomeFetched, Attempts: 1},
+			{XPath: "/a[2]", Outcome: OutcomeFetched, Attempts: 3, Retries: 1},
+			{XPath: "/a[3]", Outcome: OutcomeDuplicate},
+			{XPath: "/a[4]", Outcome: OutcomeTimeout, Attempts: 5, Retries: 4, Error: "context deadline exceeded"},
+			{XPath: "/a[5]", Outcome: OutcomeSkipped},
+		},
+	}
+}
+
+func TestReport_SummaryAndFailures(t *testing.T) {
+	r := sampleReport()
+	if got, want := r.Summary(), "5 targets in 1m30s: 2 fetched, 1 duplicate, 1 timeout, 1 skipped (5 retries)"; got != want {
+		t.Fatalf("Summary() = %q, want %q", got, want)
+	}
+	if f := r.Failures(); len(f) != 1 || f[0].XPath != "/a[4]" {
+		t.Fatalf("unexpected failures: %+v", f)
+	}
+	if got := (&Report{}).Summary(); got != "0 targets in 0s" {
+		t.Fatalf("empty Summary() = %q", got)
+	}
+}
+
+func TestSaveReports(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "report.json")
+	if err := SaveReports(path, []*Report{sampleReport()}); err != nil {
+		t.Fatalf("SaveReports: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var got []Report
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got) != 1 || len(got[0].Targets) != 5 || got[0].Targets[3].Outcome != OutcomeTimeout {
+		t.Fatalf("unexpected round trip: %+v", got)
+	}
+}
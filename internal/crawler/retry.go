@@ -0,0 +1,129 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// ErrOutOfScope reports a page outside the documentation site. It is permanent.
+var ErrOutOfScope = errors.New("out of scope")
+
+// permanentError marks an error that retrying cannot fix.
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that RetryPolicy gives up on it immediately.
+// Permanent(nil) is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// IsRetryable is the default error classification. Cancellation, a browser
+// that went away and errors marked with Permanent or ErrOutOfScope are
+// permanent; everything else (timeouts, detached nodes, empty content that
+// may still be rendering) is assumed transient.
+func IsRetryable(err error) bool {
+	var p permanentError
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &p),
+		errors.Is(err, ErrOutOfScope),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, chromedp.ErrChannelClosed),
+		errors.Is(err, chromedp.ErrInvalidContext),
+		errors.Is(err, chromedp.ErrInvalidTarget):
+		return false
+	}
+	return true
+}
+
+// RetryPolicy retries an operation with exponential backoff. A policy is
+// immutable configuration: every Do call gets its own Backoff, so one policy
+// can be shared by concurrent crawls.
+type RetryPolicy struct {
+	MaxAttempts int
+	Base        time.Duration
+	Max         time.Duration
+	Factor      float64
+	Jitter      float64
+	// Retryable classifies errors; nil uses IsRetryable.
+	Retryable func(error) bool
+}
+
+// RetryOption configures a RetryPolicy.
+type RetryOption func(*RetryPolicy)
+
+// WithMaxAttempts sets the number of attempts, including the first. Values
+// below 1 are clamped to 1.
+func WithMaxAttempts(n int) RetryOption {
+	if n < 1 {
+		n = 1
+	}
+	return func(p *RetryPolicy) { p.MaxAttempts = n }
+}
+
+// WithRetryBackoff sets the backoff parameters; see NewBackoff.
+func WithRetryBackoff(base, max time.Duration, factor, jitter float64) RetryOption {
+	return func(p *RetryPolicy) { p.Base, p.Max, p.Factor, p.Jitter = base, max, factor, jitter }
+}
+
+// WithClassifier sets the function deciding whether an error is retryable.
+func WithClassifier(fn func(error) bool) RetryOption {
+	return func(p *RetryPolicy) { p.Retryable = fn }
+}
+
+// NewRetryPolicy returns a policy of 5 attempts backing off from 500ms to 20s
+// (factor 2, 20% jitter), as the crawler has always used.
+func NewRetryPolicy(opts ...RetryOption) *RetryPolicy {
+	p := &RetryPolicy{
+		MaxAttempts: 5,
+		Base:        500 * time.Millisecond,
+		Max:         20 * time.Second,
+		Factor:      2,
+		Jitter:      0.2,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
+	}
+	return p
+}
+
+// Do calls fn until it succeeds, returns a permanent error, the attempts run
+// out or ctx is done, sleeping between attempts without outliving ctx. It
+// returns the number of attempts made and the last error.
+func (p *RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context) error) (int, error) {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	b := NewBackoff(p.Base, p.Max, p.Factor, p.Jitter)
+	var err error
+	attempts := 0
+	for attempts < max(p.MaxAttempts, 1) {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if err == nil {
+				err = ctxErr
+			}
+			return attempts, err
+		}
+		attempts++
+		if err = fn(ctx); err == nil || !retryable(err) {
+			return attempts, err
+		}
+		if attempts < p.MaxAttempts {
+			sleep(ctx, b.Next())
+		}
+	}
+	return attempts, err
+}
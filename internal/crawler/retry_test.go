@@ -0,0 +1,79 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+func fastPolicy(opts ...RetryOption) *RetryPolicy {
+	return NewRetryPolicy(append([]RetryOption{WithRetryBackoff(time.Millisecond, time.Millisecond, 1, 0)}, opts...)...)
+}
+
+func TestRetryPolicy_RetriesTransientErrors(t *testing.T) {
+	calls := 0
+	n, err := fastPolicy().Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return context.DeadlineExceeded
+		}
+		return nil
+	})
+	if err != nil || n != 3 {
+		t.Fatalf("Do() = %d, %v; want 3, nil", n, err)
+	}
+}
+
+func TestRetryPolicy_StopsOnPermanentErrors(t *testing.T) {
+	for _, perm := range []error{
+		Permanent(errors.New("page not found")),
+		fmt.Errorf("visit: %w", ErrOutOfScope),
+		chromedp.ErrChannelClosed,
+		context.Canceled,
+	} {
+		n, err := fastPolicy().Do(context.Background(), func(context.Context) error { return perm })
+		if n != 1 || !errors.Is(err, perm) {
+			t.Errorf("%v: Do() = %d, %v; want a single attempt", perm, n, err)
+		}
+	}
+}
+
+func TestRetryPolicy_GivesUpAfterMaxAttempts(t *testing.T) {
+	boom := errors.New("detached node")
+	n, err := fastPolicy(WithMaxAttempts(2)).Do(context.Background(), func(context.Context) error { return boom })
+	if n != 2 || !errors.Is(err, boom) {
+		t.Fatalf("Do() = %d, %v; want 2, %v", n, err, boom)
+	}
+}
+
+func TestRetryPolicy_HonoursContextDuringSleep(t *testing.T) {
+	p := NewRetryPolicy(WithRetryBackoff(time.Minute, time.Minute, 1, 0))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	n, err := p.Do(ctx, func(context.Context) error { return errors.New("not yet") })
+	if time.Since(start) > time.Second {
+		t.Fatalf("Do slept through cancellation")
+	}
+	if n != 1 || err == nil {
+		t.Fatalf("Do() = %d, %v; want 1 attempt and an error", n, err)
+	}
+}
+
+func TestRetryPolicy_CustomClassifier(t *testing.T) {
+	p := fastPolicy(WithClassifier(func(error) bool { return false }))
+	n, _ := p.Do(context.Background(), func(context.Context) error { return errors.New("x") })
+	if n != 1 {
+		t.Fatalf("classifier should stop retries, got %d attempts", n)
+	}
+}
+
+func TestPermanentNil(t *testing.T) {
+	if Permanent(nil) != nil {
+		t.Fatalf("Permanent(nil) should be nil")
+	}
+}
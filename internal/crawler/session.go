@@ -0,0 +1,191 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+)
+
+// DefaultUserAgent is the user agent sent by sessions unless overridden.
+const DefaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36"
+
+// Session owns a single browser shared by any number of crawls. Each crawl
+// runs in its own tab, so the browser is started once per process instead of
+// once per target.
+type Session struct {
+	// ExecPath is the Chrome executable; empty uses chromedp's lookup.
+	ExecPath string
+	// RemoteURL connects to an already running browser via its DevTools
+	// websocket or HTTP endpoint instead of launching one. When set, the
+	// launch options below are ignored.
+	RemoteURL        string
+	UserDataDir      string
+	Proxy            string
+	WindowWidth      int
+	WindowHeight     int
+	Headless         bool
+	DisableGPU       bool
+	IgnoreCertErrors bool
+	UserAgent        string
+
+	mu          sync.Mutex
+	browser     context.Context
+	cancelAlloc context.CancelFunc
+	cancel      context.CancelFunc
+}
+
+// SessionOption configures a Session.
+type SessionOption func(*Session)
+
+// WithExecPath sets the Chrome executable to launch.
+func WithExecPath(path string) SessionOption { return func(s *Session) { s.ExecPath = path } }
+
+// WithRemoteURL attaches to a running browser (chromedp.NewRemoteAllocator)
+// instead of launching one.
+func WithRemoteURL(url string) SessionOption { return func(s *Session) { s.RemoteURL = url } }
+
+// WithUserDataDir sets Chrome's profile directory.
+func WithUserDataDir(dir string) SessionOption { return func(s *Session) { s.UserDataDir = dir } }
+
+// WithProxy sets the proxy server, e.g. "http://127.0.0.1:8080".
+func WithProxy(proxy string) SessionOption { return func(s *Session) { s.Proxy = proxy } }
+
+// WithWindowSize sets the browser window size. Non-positive values keep
+// Chrome's default.
+func WithWindowSize(width, height int) SessionOption {
+	if width <= 0 || height <= 0 {
+		width, height = 0, 0
+	}
+	return func(s *Session) { s.WindowWidth, s.WindowHeight = width, height }
+}
+
+// WithSessionHeadless sets whether to run Chrome in headless mode.
+func WithSessionHeadless(b bool) SessionOption { return func(s *Session) { s.Headless = b } }
+
+// WithDisableGPU sets whether to pass --disable-gpu.
+func WithDisableGPU(b bool) SessionOption { return func(s *Session) { s.DisableGPU = b } }
+
+// WithIgnoreCertErrors sets whether to pass --ignore-certificate-errors.
+func WithIgnoreCertErrors(b bool) SessionOption { return func(s *Session) { s.IgnoreCertErrors = b } }
+
+// WithUserAgent sets the user agent. Empty keeps Chrome's own.
+func WithUserAgent(ua string) SessionOption { return func(s *Session) { s.UserAgent = ua } }
+
+// NewSession constructs a Session. Defaults match the flags the crawler has
+// always used: headless, GPU disabled, certificate errors ignored and
+// DefaultUserAgent.
+func NewSession(opts ...SessionOption) *Session {
+	s := &Session{
+		Headless:         true,
+		DisableGPU:       true,
+		IgnoreCertErrors: true,
+		UserAgent:        DefaultUserAgent,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
+}
+
+// execFlags returns the command-line switches passed to a launched Chrome on
+// top of chromedp.DefaultExecAllocatorOptions.
+func (s *Session) execFlags() map[string]any {
+	flags := map[string]any{
+		"headless":                  s.Headless,
+		"disable-gpu":               s.DisableGPU,
+		"ignore-certificate-errors": s.IgnoreCertErrors,
+	}
+	if s.UserAgent != "" {
+		flags["user-agent"] = s.UserAgent
+	}
+	if s.UserDataDir != "" {
+		flags["user-data-dir"] = s.UserDataDir
+	}
+	if s.Proxy != "" {
+		flags["proxy-server"] = s.Proxy
+	}
+	if s.WindowWidth > 0 && s.WindowHeight > 0 {
+		flags["window-size"] = fmt.Sprintf("%d,%d", s.WindowWidth, s.WindowHeight)
+	}
+	return flags
+}
+
+// Open starts (or attaches to) the browser. The browser lives until Close or
+// until ctx is cancelled. Opening an open session is a no-op.
+func (s *Session) Open(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.browser != nil {
+		return nil
+	}
+
+	var (
+		alloc       context.Context
+		cancelAlloc context.CancelFunc
+	)
+	if s.RemoteURL != "" {
+		alloc, cancelAlloc = chromedp.NewRemoteAllocator(ctx, s.RemoteURL)
+	} else {
+		opts := append([]chromedp.ExecAllocatorOption{}, chromedp.DefaultExecAllocatorOptions[:]...)
+		for name, v := range s.execFlags() {
+			opts = append(opts, chromedp.Flag(name, v))
+		}
+		if s.ExecPath != "" {
+			opts = append(opts, chromedp.ExecPath(s.ExecPath))
+		}
+		alloc, cancelAlloc = chromedp.NewExecAllocator(ctx, opts...)
+	}
+
+	browser, cancel := chromedp.NewContext(alloc)
+	// Running with no actions starts the browser and its first tab
+	if err := chromedp.Run(browser); err != nil {
+		cancel()
+		cancelAlloc()
+		return fmt.Errorf("start browser: %w", err)
+	}
+	s.browser, s.cancel, s.cancelAlloc = browser, cancel, cancelAlloc
+	return nil
+}
+
+// NewTab opens a new tab in the session's browser. The tab is closed when the
+// returned cancel function is called or when ctx is done.
+func (s *Session) NewTab(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	s.mu.Lock()
+	browser := s.browser
+	s.mu.Unlock()
+	if browser == nil {
+		return nil, nil, errors.New("session is not open")
+	}
+	tab, cancel := chromedp.NewContext(browser)
+	stop := context.AfterFunc(ctx, cancel)
+	return tab, func() {
+		stop()
+		cancel()
+	}, nil
+}
+
+// Close shuts the browser down (or detaches from a remote one). Closing a
+// closed session is a no-op.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.browser == nil {
+		return nil
+	}
+	var err error
+	if s.RemoteURL == "" {
+		err = chromedp.Cancel(s.browser)
+	}
+	s.cancel()
+	s.cancelAlloc()
+	s.browser, s.cancel, s.cancelAlloc = nil, nil, nil
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+	return err
+}
@@ -0,0 +1,42 @@
+//go:build e2e
+
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chromedp/chromedp"
+)
+
+func TestSession_TabsShareBrowser_E2E(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<!doctype html><html><head><title>` + r.URL.Path + `</title></head><body></body></html>`))
+	}))
+	defer srv.Close()
+
+	s := NewSession()
+	if err := s.Open(context.Background()); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer s.Close()
+
+	for _, path := range []string{"/one", "/two"} {
+		tab, cancel, err := s.NewTab(context.Background())
+		if err != nil {
+			t.Fatalf("NewTab: %v", err)
+		}
+		var title string
+		if err := chromedp.Run(tab, chromedp.Navigate(srv.URL+path), chromedp.Title(&title)); err != nil {
+			cancel()
+			t.Fatalf("run in tab: %v", err)
+		}
+		cancel()
+		if title != path {
+			t.Fatalf("title = %q, want %q", title, path)
+		}
+	}
+}
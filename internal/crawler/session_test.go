@@ -0,0 +1,59 @@
+package crawler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestNewSession_DefaultFlags(t *testing.T) {
+	got := NewSession().execFlags()
+	want := map[string]any{
+		"headless":                  true,
+		"disable-gpu":               true,
+		"ignore-certificate-errors": true,
+		"user-agent":                DefaultUserAgent,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("execFlags() = %v, want %v", got, want)
+	}
+}
+
+func TestNewSession_Options(t *testing.T) {
+	s := NewSession(
+		WithSessionHeadless(false),
+		WithIgnoreCertErrors(false),
+		WithUserAgent(""),
+		WithUserDataDir("/tmp/profile"),
+		WithProxy("http://127.0.0.1:3128"),
+		WithWindowSize(1280, 800),
+		WithExecPath("/opt/chrome"),
+	)
+	got := s.execFlags()
+	want := map[string]any{
+		"headless":                  false,
+		"disable-gpu":               true,
+		"ignore-certificate-errors": false,
+		"user-data-dir":             "/tmp/profile",
+		"proxy-server":              "http://127.0.0.1:3128",
+		"window-size":               "1280,800",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("execFlags() = %v, want %v", got, want)
+	}
+	if s.ExecPath != "/opt/chrome" {
+		t.Fatalf("ExecPath = %q", s.ExecPath)
+	}
+	if s := NewSession(WithWindowSize(-1, 800)); s.WindowWidth != 0 || s.WindowHeight != 0 {
+		t.Fatalf("invalid window size should be ignored, got %dx%d", s.WindowWidth, s.WindowHeight)
+	}
+}
+
+func TestSession_NewTabRequiresOpen(t *testing.T) {
+	if _, _, err := NewSession().NewTab(context.Background()); err == nil {
+		t.Fatalf("expected error for unopened session")
+	}
+	if err := NewSession().Close(); err != nil {
+		t.Fatalf("closing an unopened session: %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/chromedp/chromedp"
+
+	"maplestory-world-llms-txt/internal/document"
+)
+
+// TreeNode is one entry of the documentation navigation tree.
+type TreeNode struct {
+	Label string `json:"label"`
+	Depth int    `json:"depth"`
+	Leaf  bool   `json:"leaf"`
+	// URL is the page the entry leads to; empty when it was not resolved.
+	URL      string      `json:"url,omitempty"`
+	Children []*TreeNode `json:"children,omitempty"`
+
+	// xpath locates the entry in the page it was captured from.
+	xpath string
+}
+
+// NavTree is a snapshot of the navigation tree of one site section.
+type NavTree struct {
+	StartURL string        `json:"startUrl"`
+	Lang     string        `json:"lang"`
+	Kind     document.Kind `json:"kind"`
+	Nodes    []*TreeNode   `json:"nodes"`
+}
+
+// Walk calls fn for every node in depth-first order.
+func (t *NavTree) Walk(fn func(*TreeNode)) {
+	var walk func([]*TreeNode)
+	walk = func(nodes []*TreeNode) {
+		for _, n := range nodes {
+			fn(n)
+			walk(n.Children)
+		}
+	}
+	walk(t.Nodes)
+}
+
+// Count returns the number of nodes and leaves in the tree.
+func (t *NavTree) Count() (nodes, leaves int) {
+	t.Walk(func(n *TreeNode) {
+		nodes++
+		if n.Leaf {
+			leaves++
+		}
+	})
+	return nodes, leaves
+}
+
+// flatNode is a tree entry as read from the page, in document order.
+type flatNode struct {
+	Label string `json:"label"`
+	Depth int    `json:"depth"`
+	Leaf  bool   `json:"leaf"`
+	Href  string `json:"href"`
+	XPath string `json:"xpath"`
+}
+
+// buildTree nests flat entries by depth. An entry deeper than its
+// predecessor's children level is attached to the nearest shallower entry.
+func buildTree(flat []flatNode) []*TreeNode {
+	var (
+		roots []*TreeNode
+		stack []*TreeNode
+	)
+	for _, f := range flat {
+		n := &TreeNode{Label: f.Label, Depth: f.Depth, Leaf: f.Leaf, URL: f.Href, xpath: f.XPath}
+		for len(stack) > 0 && stack[len(stack)-1].Depth >= n.Depth {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, n)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, n)
+		}
+		stack = append(stack, n)
+	}
+	return roots
+}
+
+// snapshotTreeJS lists every entry of the navigation tree. An entry's depth
+// is the number of entries enclosing it, its label is its own text without
+// that of nested entries, and it is a leaf unless it owns a toggle.
+const snapshotTreeJS = `(() => {
+  const container = document.querySelector(%s);
+  if (!container) return null;
+  const entrySel = %s;
+  function owner(el){ return el.closest(entrySel); }
+  function xpathFor(el){
+    function idx(e){ let i=1; for(let s=e.previousSibling; s; s=s.previousSibling){ if(s.nodeType===1 && s.nodeName===e.nodeName) i++; } return i; }
+    const seg=[]; for(let e=el; e && e.nodeType===1; e=e.parentNode){ seg.unshift(e.nodeName.toLowerCase()+'['+idx(e)+']'); }
+    return '/'+seg.join('/');
+  }
+  const res = [];
+  container.querySelectorAll(entrySel).forEach(el => {
+    let depth = 0;
+    for (let p = el.parentElement ? owner(el.parentElement) : null; p && container.contains(p); p = p.parentElement ? owner(p.parentElement) : null) depth++;
+    const parts = [];
+    const walker = document.createTreeWalker(el, NodeFilter.SHOW_TEXT);
+    for (let t = walker.nextNode(); t; t = walker.nextNode()) {
+      if (owner(t.parentElement) === el) parts.push(t.nodeValue);
+    }
+    const label = parts.join(' ').replace(/\s+/g, ' ').trim();
+    if (!label) return;
+    let leaf = true;
+    el.querySelectorAll('.isHavingChildren').forEach(t => { if (owner(t) === el) leaf = false; });
+    let href = '';
+    const a = el.querySelector('a[href]');
+    if (a && owner(a) === el) href = a.href;
+    res.push({label: label, depth: depth, leaf: leaf, href: href, xpath: xpathFor(el)});
+  });
+  return res;
+})()`
+
+// snapshotTree reads the (expanded) navigation tree from the page.
+func snapshotTree(ctx context.Context) ([]*TreeNode, error) {
+	js := fmt.Sprintf(snapshotTreeJS, strconv.Quote(navContainerSel), strconv.Quote(treeNodeSel))
+	var flat []flatNode
+	if err := chromedp.Run(ctx, chromedp.Evaluate(js, &flat)); err != nil {
+		return nil, fmt.Errorf("snapshot tree: %w", err)
+	}
+	return buildTree(flat), nil
+}
+
+// resolveURLs fills the URL of every node from urls, keyed by XPath, leaving
+// nodes whose URL is already known untouched.
+func resolveURLs(nodes []*TreeNode, urls map[string]string) {
+	t := NavTree{Nodes: nodes}
+	t.Walk(func(n *TreeNode) {
+		if n.URL == "" {
+			n.URL = urls[n.xpath]
+		}
+	})
+}
+
+// SortDocuments orders docs by the position of their URL in the navigation
+// tree, so the output does not depend on the order pages were clicked or
+// fetched in. Documents missing from the tree follow, ordered by ID.
+func (t *NavTree) SortDocuments(docs []document.Document) {
+	pos := make(map[string]int)
+	t.Walk(func(n *TreeNode) {
+		if _, ok := pos[n.URL]; n.URL != "" && !ok {
+			pos[n.URL] = len(pos)
+		}
+	})
+	rank := func(d document.Document) int {
+		if p, ok := pos[d.URL]; ok {
+			return p
+		}
+		return len(pos)
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		ri, rj := rank(docs[i]), rank(docs[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return ri == len(pos) && docs[i].ID < docs[j].ID
+	})
+}
+
+// Tree expands the navigation tree at url and returns a snapshot of it
+// without fetching any document. With resolve, every leaf is clicked to learn
+// the URL it leads to, which costs one page load per leaf.
+func (c *Crawler) Tree(parent context.Context, url string, resolve bool) (*NavTree, error) {
+	tree := &NavTree{StartURL: url, Lang: document.LangOf(url), Kind: document.KindOf(url)}
+	ctx, closeTab, err := c.openStart(parent, url)
+	if err != nil {
+		return tree, err
+	}
+	defer closeTab()
+
+	if _, err := expandTree(ctx); err != nil {
+		return tree, err
+	}
+	if tree.Nodes, err = snapshotTree(ctx); err != nil {
+		return tree, err
+	}
+	if !resolve {
+		return tree, nil
+	}
+
+	host := hostOf(url)
+	delay := NewHumanDelay(c.ClickDelay, c.DelayJitter)
+	urls := make(map[string]string)
+	var walkErr error
+	tree.Walk(func(n *TreeNode) {
+		if walkErr != nil || !n.Leaf || n.URL != "" {
+			return
+		}
+		if walkErr = c.Limiter.Wait(ctx, host); walkErr != nil {
+			return
+		}
+		// Entries that fail to click keep an empty URL
+		_, _ = expandTree(ctx)
+		if err := clickByXPath(ctx, n.xpath); err != nil {
+			return
+		}
+		sleep(ctx, delay.Next())
+		var loc string
+		if err := chromedp.Run(ctx, chromedp.Location(&loc)); err == nil {
+			urls[n.xpath] = loc
+		}
+	})
+	resolveURLs(tree.Nodes, urls)
+	return tree, walkErr
+}
+
+// Summary returns a short description such as "en api: 120 nodes, 94 leaves".
+func (t *NavTree) Summary() string {
+	nodes, leaves := t.Count()
+	return fmt.Sprintf("%s %s: %d nodes, %d leaves", t.Lang, t.Kind, nodes, leaves)
+}
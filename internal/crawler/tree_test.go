@@ -0,0 +1,94 @@
+package crawler
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/document"
+)
+
+func sampleFlat() []flatNode {
+	return []flatNode{
+		{Label: "Getting Started", Depth: 0, XPath: "/x[1]"},
+		{Label: "Install", Depth: 1, Leaf: true, XPath: "/x[2]"},
+		{Label: "Workspace", Depth: 1, XPath: "/x[3]"},
+		{Label: "Explorer", Depth: 2, Leaf: true, XPath: "/x[4]", Href: "https://example.com/explorer"},
+		{Label: "API", Depth: 0, XPath: "/x[5]"},
+		{Label: "Skipped level", Depth: 2, Leaf: true, XPath: "/x[6]"},
+	}
+}
+
+func TestBuildTree_NestsByDepth(t *testing.T) {
+	roots := buildTree(sampleFlat())
+	if len(roots) != 2 || roots[0].Label != "Getting Started" || roots[1].Label != "API" {
+		t.Fatalf("unexpected roots: %+v", roots)
+	}
+	ws := roots[0].Children[1]
+	if ws.Label != "Workspace" || len(ws.Children) != 1 || ws.Children[0].Label != "Explorer" {
+		t.Fatalf("unexpected nesting under Workspace: %+v", ws)
+	}
+	if c := roots[1].Children; len(c) != 1 || c[0].Label != "Skipped level" {
+		t.Fatalf("deeper entry should attach to nearest shallower one: %+v", c)
+	}
+	tree := &NavTree{Lang: "en", Kind: "reference", Nodes: roots}
+	if nodes, leaves := tree.Count(); nodes != 6 || leaves != 3 {
+		t.Fatalf("Count() = %d, %d", nodes, leaves)
+	}
+	if got := tree.Summary(); got != "en reference: 6 nodes, 3 leaves" {
+		t.Fatalf("Summary() = %q", got)
+	}
+}
+
+func TestResolveURLs_KeepsKnownURLs(t *testing.T) {
+	roots := buildTree(sampleFlat())
+	resolveURLs(roots, map[string]string{
+		"/x[2]": "https://example.com/install",
+		"/x[4]": "https://example.com/other",
+	})
+	if u := roots[0].Children[0].URL; u != "https://example.com/install" {
+		t.Fatalf("Install URL = %q", u)
+	}
+	if u := roots[0].Children[1].Children[0].URL; u != "https://example.com/explorer" {
+		t.Fatalf("href from the page should win, got %q", u)
+	}
+}
+
+func TestNavTree_JSON(t *testing.T) {
+	tree := &NavTree{StartURL: "https://example.com", Lang: "en", Kind: "api", Nodes: buildTree(sampleFlat())}
+	data, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if strings.Contains(string(data), "/x[1]") {
+		t.Fatalf("XPaths are page-specific and should not be exported:\n%s", data)
+	}
+	var got NavTree
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got.Nodes) != 2 || got.Nodes[0].Children[1].Children[0].Label != "Explorer" || !got.Nodes[0].Children[0].Leaf {
+		t.Fatalf("unexpected round trip: %+v", got)
+	}
+}
+
+func TestNavTree_SortDocuments(t *testing.T) {
+	roots := buildTree(sampleFlat())
+	resolveURLs(roots, map[string]string{"/x[2]": "https://example.com/install", "/x[6]": "https://example.com/skipped"})
+	tree := &NavTree{Nodes: roots}
+	docs := []document.Document{
+		{ID: "b", URL: "https://example.com/unknown-b"},
+		{ID: "skipped", URL: "https://example.com/skipped"},
+		{ID: "a", URL: "https://example.com/unknown-a"},
+		{ID: "explorer", URL: "https://example.com/explorer"},
+		{ID: "install", URL: "https://example.com/install"},
+	}
+	tree.SortDocuments(docs)
+	var got []string
+	for _, d := range docs {
+		got = append(got, d.ID)
+	}
+	if strings.Join(got, " ") != "install explorer skipped a b" {
+		t.Fatalf("unexpected order %v", got)
+	}
+}
@@ -1,9 +1,115 @@
+// Package document defines the documentation model shared by every stage of
+// the pipeline: the crawler fills in what it fetched, the conversion step adds
+// Markdown, and publishing stages read the result. Documents are persisted as
+// versioned JSON (see EncodeJSON and DecodeJSON) so stages can evolve independently.
 package document
 
-// Document represents a crawled document item.
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Kind classifies a document by the part of the site it comes from.
+type Kind string
+
+const (
+	KindReference Kind = "reference"
+	KindAPI       Kind = "api"
+)
+
+// Document represents a crawled documentation page.
 type Document struct {
-	Title     string `json:"title"`
-	URL       string `json:"url"`
-	InnerHTML string `json:"innerHTML"`
-	Content   string `json:"content"`
+	// ID is a stable, URL-derived identifier such as "en/docs/472" or
+	// "en/apiReference/Components/AIComponent" (see IDOf).
+	ID   string `json:"id"`
+	Lang string `json:"lang"`
+	Kind Kind   `json:"kind"`
+	// Breadcrumb is the navigation path leading to the page, excluding its title.
+	Breadcrumb []string `json:"breadcrumb,omitempty"`
+	Title      string   `json:"title"`
+	URL        string   `json:"url"`
+	// HTML is the raw inner HTML of the content container.
+	HTML string `json:"html"`
+	// Markdown is the converted content; empty until the conversion stage ran.
+	Markdown string `json:"markdown,omitempty"`
+	Fetch    Fetch  `json:"fetch"`
+	// ContentHash is the hex SHA-256 of HTML, used to detect changed pages.
+	ContentHash string `json:"contentHash,omitempty"`
+	// Links are the absolute URLs the page links to, in document order.
+	Links []string `json:"links,omitempty"`
+}
+
+// Fetch records how a document was fetched.
+type Fetch struct {
+	FetchedAt  time.Time `json:"fetchedAt,omitzero"`
+	DurationMS int64     `json:"durationMs,omitempty"`
+	Attempts   int       `json:"attempts,omitempty"`
+}
+
+// New returns a Document for a fetched page with ID, language, kind and
+// content hash derived from the URL and HTML.
+func New(title, rawURL, html string) Document {
+	return Document{
+		ID:          IDOf(rawURL),
+		Lang:        LangOf(rawURL),
+		Kind:        KindOf(rawURL),
+		Title:       title,
+		URL:         rawURL,
+		HTML:        html,
+		ContentHash: Hash(html),
+	}
+}
+
+// Hash returns the hex SHA-256 of s.
+func Hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// LangOf returns the site language of a document URL ("en", "ko", ...), taken
+// from the first path segment, or "" when it cannot be determined.
+func LangOf(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	seg := strings.SplitN(strings.Trim(u.Path, "/"), "/", 2)[0]
+	if len(seg) == 2 {
+		return strings.ToLower(seg)
+	}
+	return ""
+}
+
+// KindOf classifies a document URL as reference documentation or API reference.
+func KindOf(raw string) Kind {
+	if strings.Contains(raw, "/apiReference") {
+		return KindAPI
+	}
+	return KindReference
+}
+
+// IDOf derives a stable identifier from a document URL:
+//
+//	https://.../en/docs/?postId=472                    -> en/docs/472
+//	https://.../en/apiReference/Components/AIComponent -> en/apiReference/Components/AIComponent
+func IDOf(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return url.PathEscape(raw)
+	}
+	lang := LangOf(raw)
+	if id := u.Query().Get("postId"); id != "" {
+		if lang == "" {
+			return "docs/" + id
+		}
+		return lang + "/docs/" + id
+	}
+	p := strings.Trim(u.Path, "/")
+	if lang == "" {
+		return p
+	}
+	return lang + "/" + strings.TrimPrefix(p, lang+"/")
 }
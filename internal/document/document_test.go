@@ -0,0 +1,30 @@
+package document
+
+import "testing"
+
+func TestIDOf(t *testing.T) {
+	cases := map[string]string{
+		"https://maplestoryworlds-creators.nexon.com/en/docs/?postId=472":                    "en/docs/472",
+		"https://maplestoryworlds-creators.nexon.com/ko/docs/?postId=472":                    "ko/docs/472",
+		"https://maplestoryworlds-creators.nexon.com/en/apiReference/Components/AIComponent": "en/apiReference/Components/AIComponent",
+		"https://example.com/docs/?postId=5":                                                 "docs/5",
+	}
+	for in, want := range cases {
+		if got := IDOf(in); got != want {
+			t.Errorf("IDOf(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	d := New("AIComponent", "https://maplestoryworlds-creators.nexon.com/ko/apiReference/Components/AIComponent", "<p>x</p>")
+	if d.ID != "ko/apiReference/Components/AIComponent" || d.Lang != "ko" || d.Kind != KindAPI {
+		t.Fatalf("unexpected identity: %+v", d)
+	}
+	if d.ContentHash != Hash("<p>x</p>") || len(d.ContentHash) != 64 {
+		t.Fatalf("unexpected hash %q", d.ContentHash)
+	}
+	if KindOf("https://maplestoryworlds-creators.nexon.com/en/docs/?postId=1") != KindReference {
+		t.Fatalf("expected reference kind")
+	}
+}
@@ -0,0 +1,113 @@
+package document
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SchemaVersion is the version written by EncodeJSON. Bump it when the
+// serialized shape of Document changes and teach DecodeJSON to migrate older
+// versions.
+const SchemaVersion = 1
+
+// file is the versioned on-disk envelope.
+type file struct {
+	Version   int        `json:"version"`
+	Documents []Document `json:"documents"`
+}
+
+// legacyDocument is the unversioned shape written before SchemaVersion 1: a
+// bare JSON array of these objects.
+type legacyDocument struct {
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	InnerHTML string `json:"innerHTML"`
+	Content   string `json:"content"`
+}
+
+// EncodeJSON encodes the docs slice as pretty JSON wrapped in a versioned envelope.
+func EncodeJSON(docs []Document) ([]byte, error) {
+	if docs == nil {
+		docs = []Document{}
+	}
+	return json.MarshalIndent(file{Version: SchemaVersion, Documents: docs}, "", "  ")
+}
+
+// DecodeJSON decodes documents written by EncodeJSON or by the unversioned
+// crawler output that preceded it. Files from a newer schema are rejected.
+func DecodeJSON(data []byte) ([]Document, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var legacy []legacyDocument
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return nil, err
+		}
+		docs := make([]Document, 0, len(legacy))
+		for _, l := range legacy {
+			d := New(l.Title, l.URL, l.InnerHTML)
+			d.Markdown = l.Content
+			docs = append(docs, d)
+		}
+		return docs, nil
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if f.Version < 1 || f.Version > SchemaVersion {
+		return nil, fmt.Errorf("unsupported document schema version %d (supported: 1-%d)", f.Version, SchemaVersion)
+	}
+	return f.Documents, nil
+}
+
+// SaveJSON writes the docs slice as JSON to the given file path.
+func SaveJSON(path string, docs []Document) error {
+	data, err := EncodeJSON(docs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadJSON reads a docs slice previously written by SaveJSON.
+func LoadJSON(path string) ([]Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeJSON(data)
+}
+
+// EncodeCSV encodes the docs slice as CSV with header. The content column
+// holds the converted Markdown.
+func EncodeCSV(docs []Document) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	// header
+	if err := w.Write([]string{"title", "url", "content"}); err != nil {
+		return nil, err
+	}
+	for _, d := range docs {
+		if err := w.Write([]string{d.Title, d.URL, d.Markdown}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SaveCSV writes the docs slice as CSV to the given file path.
+func SaveCSV(path string, docs []Document) error {
+	data, err := EncodeCSV(docs)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
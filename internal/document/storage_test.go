@@ -1,4 +1,4 @@
-package crawler
+package document
 
 import (
 	"encoding/csv"
@@ -11,8 +11,8 @@ import (
 
 func sampleDocs() []Document {
 	return []Document{
-		{Title: "Hello", URL: "https://example.com?a=1", Content: "Line1\nLine2"},
-		{Title: "World, CSV", URL: "https://example.com?a=2", Content: "Comma, inside"},
+		{Title: "Hello", URL: "https://example.com?a=1", Markdown: "Line1\nLine2"},
+		{Title: "World, CSV", URL: "https://example.com?a=2", Markdown: "Comma, inside"},
 	}
 }
 
@@ -22,15 +22,36 @@ func TestEncodeJSON_RoundTrip(t *testing.T) {
 	if err != nil {
 		t.Fatalf("EncodeJSON error: %v", err)
 	}
-	var got []Document
+	var got file
 	if err := json.Unmarshal(data, &got); err != nil {
 		t.Fatalf("unmarshal: %v", err)
 	}
-	if len(got) != len(docs) || got[1].Title != docs[1].Title || got[0].Content != docs[0].Content {
+	if got.Version != SchemaVersion {
+		t.Fatalf("expected version %d, got %d", SchemaVersion, got.Version)
+	}
+	if len(got.Documents) != len(docs) || got.Documents[1].Title != docs[1].Title || got.Documents[0].Markdown != docs[0].Markdown {
 		t.Fatalf("mismatch: got=%+v", got)
 	}
 }
 
+func TestDecodeJSON_LegacyArray(t *testing.T) {
+	data := []byte(`[{"title":"Workspace","url":"https://maplestoryworlds-creators.nexon.com/en/docs/?postId=472","innerHTML":"<p>x</p>","content":"# Workspace"}]`)
+	docs, err := DecodeJSON(data)
+	if err != nil {
+		t.Fatalf("DecodeJSON: %v", err)
+	}
+	d := docs[0]
+	if d.ID != "en/docs/472" || d.Lang != "en" || d.Kind != KindReference || d.HTML != "<p>x</p>" || d.Markdown != "# Workspace" || d.ContentHash != Hash("<p>x</p>") {
+		t.Fatalf("unexpected migrated document: %+v", d)
+	}
+}
+
+func TestDecodeJSON_RejectsNewerVersion(t *testing.T) {
+	if _, err := DecodeJSON([]byte(`{"version": 99, "documents": []}`)); err == nil {
+		t.Fatalf("expected error for newer schema version")
+	}
+}
+
 func TestSaveJSON_WritesFile(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "out.json")
@@ -42,6 +63,20 @@ func TestSaveJSON_WritesFile(t *testing.T) {
 	}
 }
 
+func TestLoadJSON_ReadsSavedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.json")
+	if err := SaveJSON(path, sampleDocs()); err != nil {
+		t.Fatalf("SaveJSON: %v", err)
+	}
+	got, err := LoadJSON(path)
+	if err != nil {
+		t.Fatalf("LoadJSON: %v", err)
+	}
+	if len(got) != 2 || got[1].URL != "https://example.com?a=2" || got[0].Markdown != "Line1\nLine2" {
+		t.Fatalf("mismatch: got=%+v", got)
+	}
+}
+
 func TestEncodeCSV_AndReadBack(t *testing.T) {
 	data, err := EncodeCSV(sampleDocs())
 	if err != nil {
@@ -0,0 +1,257 @@
+// Package glossary extracts a Korean↔English term list from the two language
+// editions of the documentation. Documents are paired by ID (postId or API
+// path) or, failing that, by navigation position; within a pair, titles and
+// headings are matched by position between anchors, the headings that read
+// the same in both languages (API class names, section names).
+package glossary
+
+import (
+	"encoding/csv"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/markdown"
+)
+
+// Source says where a term pair was found.
+type Source string
+
+const (
+	SourceTitle   Source = "title"
+	SourceHeading Source = "heading"
+)
+
+// Entry is one English term and its Korean counterpart.
+type Entry struct {
+	EN     string `json:"en"`
+	KO     string `json:"ko"`
+	Source Source `json:"source"`
+	// Count is the number of document pairs the term pair was found in.
+	Count int `json:"count"`
+	// Docs are the language-neutral keys of those pairs, e.g. docs/472.
+	Docs []string `json:"docs"`
+}
+
+// Pair is an English document and its Korean edition.
+type Pair struct {
+	Key string
+	EN  document.Document
+	KO  document.Document
+	// ByPosition is set when the pair was matched by navigation position
+	// rather than by ID.
+	ByPosition bool
+}
+
+// Key returns the language-neutral ID of a document, e.g. en/docs/472 ->
+// docs/472.
+func Key(d document.Document) string {
+	if d.Lang != "" {
+		return strings.TrimPrefix(d.ID, d.Lang+"/")
+	}
+	return d.ID
+}
+
+// Align pairs English and Korean documents by Key. Documents left over are
+// paired by their order within the same kind, which follows the navigation
+// tree, but only when both languages have the same number left; otherwise
+// position says nothing and they stay unpaired.
+func Align(en, ko []document.Document) []Pair {
+	byKey := make(map[string]int, len(ko))
+	for i, d := range ko {
+		byKey[Key(d)] = i
+	}
+	var (
+		pairs  []Pair
+		usedKO = make(map[int]bool)
+		restEN = make(map[document.Kind][]document.Document)
+	)
+	for _, d := range en {
+		if i, ok := byKey[Key(d)]; ok && !usedKO[i] {
+			usedKO[i] = true
+			pairs = append(pairs, Pair{Key: Key(d), EN: d, KO: ko[i]})
+			continue
+		}
+		restEN[d.Kind] = append(restEN[d.Kind], d)
+	}
+	restKO := make(map[document.Kind][]document.Document)
+	for i, d := range ko {
+		if !usedKO[i] {
+			restKO[d.Kind] = append(restKO[d.Kind], d)
+		}
+	}
+	for _, kind := range []document.Kind{document.KindReference, document.KindAPI} {
+		e, k := restEN[kind], restKO[kind]
+		if len(e) == 0 || len(e) != len(k) {
+			continue
+		}
+		for i := range e {
+			pairs = append(pairs, Pair{Key: Key(e[i]), EN: e[i], KO: k[i], ByPosition: true})
+		}
+	}
+	return pairs
+}
+
+// heading is a heading's level and cleaned-up text.
+type heading struct {
+	level int
+	text  string
+}
+
+// Extract returns the term pairs found in the document pairs, sorted by
+// English term and then by how often the pair occurs, so conflicting
+// translations of a term sit next to each other for review.
+func Extract(pairs []Pair) []Entry {
+	type key struct{ en, ko string }
+	var (
+		entries = make(map[key]*Entry)
+		order   []key
+	)
+	add := func(en, ko string, src Source, doc string) {
+		if !isTerm(en, ko) {
+			return
+		}
+		k := key{en, ko}
+		e, ok := entries[k]
+		if !ok {
+			e = &Entry{EN: en, KO: ko, Source: src}
+			entries[k] = e
+			order = append(order, k)
+		}
+		if n := len(e.Docs); n > 0 && e.Docs[n-1] == doc {
+			return
+		}
+		e.Count++
+		e.Docs = append(e.Docs, doc)
+	}
+
+	for _, p := range pairs {
+		add(cleanText(p.EN.Title), cleanText(p.KO.Title), SourceTitle, p.Key)
+		for _, m := range alignHeadings(headings(p.EN.Markdown), headings(p.KO.Markdown)) {
+			add(m[0].text, m[1].text, SourceHeading, p.Key)
+		}
+	}
+
+	out := make([]Entry, 0, len(order))
+	for _, k := range order {
+		out = append(out, *entries[k])
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := strings.ToLower(out[i].EN), strings.ToLower(out[j].EN)
+		if a != b {
+			return a < b
+		}
+		return out[i].Count > out[j].Count
+	})
+	return out
+}
+
+func headings(md string) []heading {
+	var out []heading
+	for _, b := range markdown.Parse(md) {
+		if b.Kind == markdown.Heading {
+			if t := cleanText(b.Title); t != "" {
+				out = append(out, heading{b.Level, t})
+			}
+		}
+	}
+	return out
+}
+
+// alignHeadings matches two heading sequences. Headings with the same text
+// in both languages are anchors, chosen as their longest common subsequence;
+// the runs between consecutive anchors are paired position by position when
+// they have the same length and the same heading levels.
+func alignHeadings(en, ko []heading) [][2]heading {
+	// lcs[i][j] is the length of the longest common subsequence of en[i:], ko[j:]
+	lcs := make([][]int, len(en)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(ko)+1)
+	}
+	for i := len(en) - 1; i >= 0; i-- {
+		for j := len(ko) - 1; j >= 0; j-- {
+			if en[i] == ko[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out [][2]heading
+	pairRun := func(e, k []heading) {
+		if len(e) != len(k) {
+			return
+		}
+		for i := range e {
+			if e[i].level != k[i].level {
+				return
+			}
+		}
+		for i := range e {
+			out = append(out, [2]heading{e[i], k[i]})
+		}
+	}
+	i, j, si, sj := 0, 0, 0, 0
+	for i < len(en) && j < len(ko) {
+		switch {
+		case en[i] == ko[j]:
+			pairRun(en[si:i], ko[sj:j])
+			i, j = i+1, j+1
+			si, sj = i, j
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	pairRun(en[si:], ko[sj:])
+	return out
+}
+
+// numberRe matches a leading section number such as "1." or "1.2", but not
+// the digits of a term like "2D Physics".
+var numberRe = regexp.MustCompile(`^\d+(\.\d+)*\.?\s+`)
+
+// cleanText drops Markdown links, emphasis, stray heading marks and leading
+// section numbers.
+func cleanText(s string) string {
+	s = markdown.PlainText(strings.TrimLeft(s, "# \t"))
+	return numberRe.ReplaceAllString(s, "")
+}
+
+// isTerm reports whether en/ko is a translation worth listing: different
+// texts, Korean on one side only.
+func isTerm(en, ko string) bool {
+	return en != "" && ko != "" && en != ko && hasHangul(ko) && !hasHangul(en)
+}
+
+func hasHangul(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Hangul, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteTSV writes entries as tab-separated values with a header row: en, ko,
+// source, count and the comma-separated document keys.
+func WriteTSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = '\t'
+	if err := cw.Write([]string{"en", "ko", "source", "count", "docs"}); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := cw.Write([]string{e.EN, e.KO, string(e.Source), strconv.Itoa(e.Count), strings.Join(e.Docs, ",")}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
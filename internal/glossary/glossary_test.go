@@ -0,0 +1,93 @@
+package glossary
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/document"
+)
+
+func doc(url, title, md string) document.Document {
+	d := document.New(title, url, "")
+	d.Markdown = md
+	return d
+}
+
+func TestAlign(t *testing.T) {
+	en := []document.Document{
+		doc("https://x.com/en/docs/?postId=1", "Workspace", ""),
+		doc("https://x.com/en/docs/?postId=2", "Hierarchy", ""),
+		doc("https://x.com/en/docs/?postId=7", "Only English", ""),
+	}
+	ko := []document.Document{
+		doc("https://x.com/ko/docs/?postId=2", "Hierarchy", ""),
+		doc("https://x.com/ko/docs/?postId=1", "Workspace", ""),
+		doc("https://x.com/ko/docs/?postId=9", "한국어만", ""),
+	}
+	pairs := Align(en, ko)
+	if len(pairs) != 3 {
+		t.Fatalf("expected 3 pairs, got %+v", pairs)
+	}
+	if pairs[0].Key != "docs/1" || pairs[0].KO.ID != "ko/docs/1" || pairs[0].ByPosition {
+		t.Fatalf("unexpected ID pair: %+v", pairs[0])
+	}
+	if !pairs[2].ByPosition || pairs[2].EN.ID != "en/docs/7" || pairs[2].KO.ID != "ko/docs/9" {
+		t.Fatalf("leftovers should pair by position: %+v", pairs[2])
+	}
+
+	// Unequal leftovers say nothing about position
+	if pairs := Align(en, ko[:2]); len(pairs) != 2 {
+		t.Fatalf("expected only the ID pairs, got %d", len(pairs))
+	}
+}
+
+func TestExtract(t *testing.T) {
+	en := "# Workspace\n\n# Course Introduction\n\n##### Reference Guide\n\n# Introducing Workspace\n\n# WorldConfig\n\n#### Adding Resource to MyDesk\n\n# Extra English Section\n\n# Context Menu\n"
+	ko := "# Workspace\n\n# 학습 과정 소개\n\n##### 참고 가이드\n\n# Workspace 소개\n\n# WorldConfig\n\n#### MyDesk에 리소스 추가하기\n\n# 콘텍스트 메뉴\n"
+	pairs := []Pair{
+		{Key: "docs/1", EN: doc("https://x.com/en/docs/?postId=1", "Workspace Guide", en), KO: doc("https://x.com/ko/docs/?postId=1", "워크스페이스 가이드", ko)},
+		{Key: "docs/2", EN: doc("https://x.com/en/docs/?postId=2", "Other", "# Course Introduction\n"), KO: doc("https://x.com/ko/docs/?postId=2", "기타", "# 학습 과정 소개\n")},
+	}
+	got := Extract(pairs)
+	want := []Entry{
+		{EN: "Course Introduction", KO: "학습 과정 소개", Source: SourceHeading, Count: 2, Docs: []string{"docs/1", "docs/2"}},
+		{EN: "Introducing Workspace", KO: "Workspace 소개", Source: SourceHeading, Count: 1, Docs: []string{"docs/1"}},
+		{EN: "Other", KO: "기타", Source: SourceTitle, Count: 1, Docs: []string{"docs/2"}},
+		{EN: "Reference Guide", KO: "참고 가이드", Source: SourceHeading, Count: 1, Docs: []string{"docs/1"}},
+		{EN: "Workspace Guide", KO: "워크스페이스 가이드", Source: SourceTitle, Count: 1, Docs: []string{"docs/1"}},
+	}
+	// The run after the WorldConfig anchor has an extra English heading, so
+	// none of it is paired
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Extract =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestWriteTSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteTSV(&buf, []Entry{{EN: "Workspace", KO: "워크스페이스", Source: SourceTitle, Count: 2, Docs: []string{"docs/1", "docs/2"}}})
+	if err != nil {
+		t.Fatalf("WriteTSV: %v", err)
+	}
+	want := "en\tko\tsource\tcount\tdocs\nWorkspace\t워크스페이스\ttitle\t2\tdocs/1,docs/2\n"
+	if buf.String() != want {
+		t.Fatalf("WriteTSV = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestCleanText(t *testing.T) {
+	cases := map[string]string{
+		"## 1.2 **Using** [Entity](https://x.com/Entity) `API`": "Using Entity API",
+		"3. Setup":          "Setup",
+		"(Avatar_Cap_A1)":   "(Avatar_Cap_A1)",
+		"2D Physics":        "2D Physics",
+		"3D Model Settings": "3D Model Settings",
+		"## 1 1.5x Speed":   "1.5x Speed",
+	}
+	for in, want := range cases {
+		if got := cleanText(in); got != want {
+			t.Errorf("cleanText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
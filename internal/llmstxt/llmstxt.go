@@ -0,0 +1,100 @@
+// Package llmstxt renders the corpus in the llms.txt format: a Markdown
+// index of every document with a one-line summary (llms.txt) and the full
+// text of every document concatenated (llms-full.txt).
+package llmstxt
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/markdown"
+)
+
+const (
+	title   = "MapleStory Worlds Documentation"
+	summary = "Creator guides and API reference for MapleStory Worlds, converted to Markdown for LLMs."
+	// summaryWidth bounds the per-document summary in runes.
+	summaryWidth = 160
+)
+
+var kindTitles = map[document.Kind]string{
+	document.KindReference: "Reference",
+	document.KindAPI:       "API Reference",
+}
+
+// Index renders llms.txt for docs: one section per kind and language, in the
+// order documents first appear.
+func Index(docs []document.Document) string {
+	var (
+		b      strings.Builder
+		groups []string
+		byKey  = make(map[string][]document.Document)
+	)
+	for _, d := range docs {
+		key := string(d.Kind) + "\x00" + d.Lang
+		if _, ok := byKey[key]; !ok {
+			groups = append(groups, key)
+		}
+		byKey[key] = append(byKey[key], d)
+	}
+
+	fmt.Fprintf(&b, "# %s\n\n> %s\n", title, summary)
+	for _, key := range groups {
+		group := byKey[key]
+		kind, lang := group[0].Kind, group[0].Lang
+		heading := kindTitles[kind]
+		if heading == "" {
+			heading = string(kind)
+		}
+		if lang != "" {
+			heading += " (" + lang + ")"
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", heading)
+		for _, d := range group {
+			fmt.Fprintf(&b, "- [%s](%s)", escapeLinkText(d.Title), d.URL)
+			if s := Summary(d.Markdown); s != "" {
+				fmt.Fprintf(&b, ": %s", s)
+			}
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// Full renders llms-full.txt: every document's Markdown, separated by a blank
+// line.
+func Full(docs []document.Document) string {
+	parts := make([]string, 0, len(docs))
+	for _, d := range docs {
+		parts = append(parts, strings.TrimRight(d.Markdown, "\n"))
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+// Summary returns the first sentence of the first paragraph in md, cut to a
+// single line of at most summaryWidth runes.
+func Summary(md string) string {
+	for _, b := range markdown.Parse(md) {
+		if b.Kind != markdown.Text {
+			continue
+		}
+		text := strings.Join(strings.Fields(b.Text()), " ")
+		if text == "" || strings.HasPrefix(text, "![") || strings.HasPrefix(text, "[") {
+			continue
+		}
+		if i := strings.Index(text, ". "); i >= 0 {
+			text = text[:i+1]
+		}
+		if utf8.RuneCountInString(text) > summaryWidth {
+			text = string([]rune(text)[:summaryWidth-1]) + "…"
+		}
+		return text
+	}
+	return ""
+}
+
+func escapeLinkText(s string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(s)
+}
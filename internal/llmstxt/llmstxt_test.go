@@ -0,0 +1,42 @@
+package llmstxt
+
+import (
+	"strings"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/corpus"
+	"maplestory-world-llms-txt/internal/document"
+)
+
+func sampleDocs() []document.Document {
+	return corpus.New([]document.Document{
+		{Title: "Workspace", URL: "https://maplestoryworlds-creators.nexon.com/en/docs/?postId=472",
+			Markdown: "# Workspace\n\n[Target: Lv.1]\n\nThe Workspace shows resources. It has folders.\n"},
+		{Title: "AIComponent", URL: "https://maplestoryworlds-creators.nexon.com/en/apiReference/Components/AIComponent",
+			Markdown: "# AIComponent\n\nUses BehaviorTree to give AI to an Entity.\n"},
+	}).Docs
+}
+
+func TestIndex(t *testing.T) {
+	got := Index(sampleDocs())
+	want := "# MapleStory Worlds Documentation\n\n> " + summary + "\n" +
+		"\n## Reference (en)\n\n- [Workspace](https://maplestoryworlds-creators.nexon.com/en/docs/?postId=472): The Workspace shows resources.\n" +
+		"\n## API Reference (en)\n\n- [AIComponent](https://maplestoryworlds-creators.nexon.com/en/apiReference/Components/AIComponent): Uses BehaviorTree to give AI to an Entity.\n"
+	if got != want {
+		t.Fatalf("unexpected llms.txt:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFull(t *testing.T) {
+	got := Full(sampleDocs())
+	if !strings.HasPrefix(got, "# Workspace") || !strings.Contains(got, "folders.\n\n# AIComponent") || !strings.HasSuffix(got, "Entity.\n") {
+		t.Fatalf("unexpected llms-full.txt: %q", got)
+	}
+}
+
+func TestSummary_Truncates(t *testing.T) {
+	s := Summary("# T\n\n" + strings.Repeat("a", 300))
+	if len([]rune(s)) != summaryWidth || !strings.HasSuffix(s, "…") {
+		t.Fatalf("unexpected summary %q", s)
+	}
+}
@@ -0,0 +1,124 @@
+// Package markdown parses the Markdown produced by the conversion step into
+// coarse blocks. It only understands the constructs mdream emits (ATX headings,
+// fenced code, pipe tables and paragraphs), which is enough to split, index and
+// validate documents without a full CommonMark implementation.
+package markdown
+
+import "strings"
+
+// Kind identifies the Markdown construct a Block was parsed from.
+type Kind int
+
+const (
+	Text Kind = iota
+	Heading
+	Code
+	Table
+)
+
+// Block is an indivisible run of Markdown lines. Fenced code and tables are
+// always a single block so callers never cut them in the middle; text blocks
+// are paragraphs separated by blank lines.
+type Block struct {
+	Kind  Kind
+	Level int    // heading level (1-6) for Heading blocks
+	Title string // heading text for Heading blocks
+	Lines []string
+	// Closed reports whether a Code block has a closing fence.
+	Closed bool
+}
+
+// Text returns the block's lines joined with newlines.
+func (b Block) Text() string { return strings.Join(b.Lines, "\n") }
+
+// Parse splits Markdown into blocks. Blank lines are dropped.
+func Parse(md string) []Block {
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	var (
+		blocks []Block
+		para   []string
+	)
+	flush := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, Block{Kind: Text, Lines: para})
+			para = nil
+		}
+	}
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case IsFence(trimmed):
+			flush()
+			fence := fenceMarker(trimmed)
+			b := Block{Kind: Code, Lines: []string{line}}
+			for i+1 < len(lines) {
+				i++
+				b.Lines = append(b.Lines, lines[i])
+				if t := strings.TrimSpace(lines[i]); strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+					b.Closed = true
+					break
+				}
+			}
+			blocks = append(blocks, b)
+		case strings.HasPrefix(trimmed, "|"):
+			flush()
+			rows := []string{line}
+			for i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "|") {
+				i++
+				rows = append(rows, lines[i])
+			}
+			blocks = append(blocks, Block{Kind: Table, Lines: rows})
+		default:
+			if level, title, ok := ParseHeading(trimmed); ok {
+				flush()
+				blocks = append(blocks, Block{Kind: Heading, Level: level, Title: title, Lines: []string{line}})
+				continue
+			}
+			para = append(para, line)
+		}
+	}
+	flush()
+	return blocks
+}
+
+// IsFence reports whether the trimmed line opens or closes a fenced code block.
+func IsFence(s string) bool {
+	return strings.HasPrefix(s, "```") || strings.HasPrefix(s, "~~~")
+}
+
+// fenceMarker returns the run of fence characters that opens a block; the
+// closing fence must be at least as long.
+func fenceMarker(s string) string {
+	n := 0
+	for n < len(s) && s[n] == s[0] {
+		n++
+	}
+	return s[:n]
+}
+
+// ParseHeading parses an ATX heading line ("## Title") and returns its level
+// and text. A trailing colon, as in "inherited from Component:", is dropped.
+func ParseHeading(s string) (int, string, bool) {
+	s = strings.TrimSpace(s)
+	level := 0
+	for level < len(s) && s[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+	if level < len(s) && s[level] != ' ' && s[level] != '\t' {
+		return 0, "", false
+	}
+	title := strings.TrimSpace(s[level:])
+	// Optional closing sequence: " ##"
+	if i := strings.LastIndex(title, " #"); i >= 0 && strings.Trim(title[i+1:], "#") == "" {
+		title = strings.TrimSpace(title[:i])
+	} else if strings.Trim(title, "#") == "" {
+		title = ""
+	}
+	return level, strings.TrimSuffix(title, ":"), true
+}
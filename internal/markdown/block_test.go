@@ -0,0 +1,54 @@
+package markdown
+
+import "testing"
+
+func TestParse_BlocksKeepCodeAndTablesWhole(t *testing.T) {
+	md := "# Title\n\nIntro line one\nline two\n\n| a |\n| --- |\n| b |\n\n```\nx\n\ny\n```\n\n##### inherited from Component:\n\ntail"
+	blocks := Parse(md)
+	kinds := []Kind{Heading, Text, Table, Code, Heading, Text}
+	if len(blocks) != len(kinds) {
+		t.Fatalf("expected %d blocks, got %d: %+v", len(kinds), len(blocks), blocks)
+	}
+	for i, k := range kinds {
+		if blocks[i].Kind != k {
+			t.Fatalf("block %d: want kind %v got %v", i, k, blocks[i].Kind)
+		}
+	}
+	if got := blocks[3].Text(); got != "```\nx\n\ny\n```" || !blocks[3].Closed {
+		t.Fatalf("code block not kept whole: %q closed=%v", got, blocks[3].Closed)
+	}
+	if len(blocks[2].Lines) != 3 {
+		t.Fatalf("expected 3 table rows, got %d", len(blocks[2].Lines))
+	}
+	if blocks[4].Level != 5 || blocks[4].Title != "inherited from Component" {
+		t.Fatalf("unexpected heading: %+v", blocks[4])
+	}
+}
+
+func TestParse_UnclosedFence(t *testing.T) {
+	blocks := Parse("```lua\nprint(1)\n")
+	if len(blocks) != 1 || blocks[0].Kind != Code || blocks[0].Closed {
+		t.Fatalf("expected one unclosed code block, got %+v", blocks)
+	}
+}
+
+func TestParseHeading(t *testing.T) {
+	cases := []struct {
+		in    string
+		level int
+		title string
+		ok    bool
+	}{
+		{"# Properties", 1, "Properties", true},
+		{"### C# ###", 3, "C#", true},
+		{"#NoSpace", 0, "", false},
+		{"####### seven", 0, "", false},
+		{"plain", 0, "", false},
+	}
+	for _, c := range cases {
+		level, title, ok := ParseHeading(c.in)
+		if level != c.level || title != c.title || ok != c.ok {
+			t.Fatalf("%q: got (%d,%q,%v) want (%d,%q,%v)", c.in, level, title, ok, c.level, c.title, c.ok)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package markdown
+
+import "strings"
+
+// CodeBlock is a fenced code block extracted from a document.
+type CodeBlock struct {
+	// Lang is the fence's info string (its first word), e.g. "lua".
+	Lang string `json:"lang,omitempty"`
+	Code string `json:"code"`
+	// Heading is the title of the nearest heading above the block.
+	Heading string `json:"heading,omitempty"`
+	// Line is the 1-based line of the opening fence.
+	Line int `json:"line"`
+}
+
+// FenceInfo returns the language of an opening fence line ("```lua" -> "lua").
+func FenceInfo(line string) string {
+	s := strings.TrimSpace(line)
+	if !IsFence(s) {
+		return ""
+	}
+	info := strings.TrimSpace(strings.TrimLeft(s, s[:1]))
+	if i := strings.IndexAny(info, " \t{"); i >= 0 {
+		info = info[:i]
+	}
+	return info
+}
+
+// CodeBlocks returns every fenced code block in md in document order.
+// Indentation inside the blocks is preserved.
+func CodeBlocks(md string) []CodeBlock {
+	var (
+		out     []CodeBlock
+		heading string
+		lines   = strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+		cursor  int
+	)
+	for _, b := range Parse(md) {
+		// Parse drops blank lines, so locate each block by scanning forward
+		line := 0
+		for i := cursor; i < len(lines); i++ {
+			if lines[i] == b.Lines[0] {
+				line, cursor = i+1, i+len(b.Lines)
+				break
+			}
+		}
+		switch b.Kind {
+		case Heading:
+			heading = b.Title
+		case Code:
+			body := b.Lines[1:]
+			if b.Closed {
+				body = body[:len(body)-1]
+			}
+			out = append(out, CodeBlock{
+				Lang:    FenceInfo(b.Lines[0]),
+				Code:    strings.Join(body, "\n"),
+				Heading: heading,
+				Line:    line,
+			})
+		}
+	}
+	return out
+}
+
+// TagFences adds a language to every opening fence that has none, using
+// detect on the block's content. Blocks detect cannot place stay untagged.
+func TagFences(md string, detect func(code string) string) string {
+	lines := strings.Split(md, "\n")
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !IsFence(trimmed) {
+			continue
+		}
+		open := i
+		fence := fenceMarker(trimmed)
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if t := strings.TrimSpace(lines[j]); strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+				end = j
+				break
+			}
+		}
+		if FenceInfo(lines[open]) == "" {
+			if lang := detect(strings.Join(lines[open+1:end], "\n")); lang != "" {
+				lines[open] = strings.TrimRight(lines[open], " \t") + lang
+			}
+		}
+		i = end
+	}
+	return strings.Join(lines, "\n")
+}
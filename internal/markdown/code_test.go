@@ -0,0 +1,47 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFenceInfo(t *testing.T) {
+	for in, want := range map[string]string{
+		"```lua":          "lua",
+		"  ~~~json title": "json",
+		"```":             "",
+		"```lua{1,3}":     "lua",
+		"not a fence":     "",
+	} {
+		if got := FenceInfo(in); got != want {
+			t.Errorf("FenceInfo(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCodeBlocks(t *testing.T) {
+	md := "# Title\n\n## Usage\n\n```lua\nlocal x = 1\n\n    print(x)\n```\n\ntext\n\n## Data\n\n```\n{}\n```\n"
+	got := CodeBlocks(md)
+	want := []CodeBlock{
+		{Lang: "lua", Code: "local x = 1\n\n    print(x)", Heading: "Usage", Line: 5},
+		{Code: "{}", Heading: "Data", Line: 15},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("CodeBlocks =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestTagFences(t *testing.T) {
+	md := "```\nlua code\n```\n\n```json\nlua code\n```\n\n```\nplain\n```\n\n````\n```\nlua nested\n````"
+	detect := func(code string) string {
+		if strings.Contains(code, "lua") {
+			return "lua"
+		}
+		return ""
+	}
+	want := "```lua\nlua code\n```\n\n```json\nlua code\n```\n\n```\nplain\n```\n\n````lua\n```\nlua nested\n````"
+	if got := TagFences(md, detect); got != want {
+		t.Fatalf("TagFences =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,67 @@
+package markdown
+
+import "strings"
+
+// Normalize returns md in a canonical form so that identical content always
+// yields identical bytes: LF line endings, no leading blank lines, runs of
+// blank lines outside fenced code collapsed into one, and exactly one final
+// newline. Outside fenced code trailing whitespace is removed; a hard line
+// break (two trailing spaces before another line of the paragraph) becomes
+// <br>. Fenced code is kept as written, blank lines and trailing whitespace
+// included.
+func Normalize(md string) string {
+	md = strings.ReplaceAll(md, "\r\n", "\n")
+	md = strings.ReplaceAll(md, "\r", "\n")
+
+	var (
+		out   []string
+		fence string
+		blank bool
+	)
+	lines := strings.Split(md, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+				line = strings.TrimRight(line, " \t")
+			}
+		case IsFence(trimmed):
+			fence = fenceMarker(trimmed)
+			line = strings.TrimRight(line, " \t")
+		case trimmed == "":
+			blank = len(out) > 0
+			continue
+		default:
+			hard := strings.HasSuffix(line, "  ") && i+1 < len(lines) && continuesParagraph(line, lines[i+1])
+			line = strings.TrimRight(line, " \t")
+			if hard {
+				line += "<br>"
+			}
+		}
+		if blank {
+			out = append(out, "")
+			blank = false
+		}
+		out = append(out, line)
+	}
+	if len(out) == 0 {
+		return ""
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+// continuesParagraph reports whether next is another line of the paragraph
+// that line belongs to, so a hard break between them is meaningful.
+func continuesParagraph(line, next string) bool {
+	line, next = strings.TrimSpace(line), strings.TrimSpace(next)
+	if next == "" || IsFence(next) || strings.HasPrefix(line, "|") {
+		return false
+	}
+	if _, _, ok := ParseHeading(line); ok {
+		return false
+	}
+	_, _, ok := ParseHeading(next)
+	return !ok
+}
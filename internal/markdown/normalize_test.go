@@ -0,0 +1,25 @@
+package markdown
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	in := "\r\n\n# Title  \r\n\r\n\r\n\nText\t\n\n\n```lua\nlocal a = 1   \n\n\nprint(a)\n```  \n\n\n\n| a |  \n"
+	want := "# Title\n\nText\n\n```lua\nlocal a = 1   \n\n\nprint(a)\n```\n\n| a |\n"
+	if got := Normalize(in); got != want {
+		t.Fatalf("Normalize() = %q, want %q", got, want)
+	}
+	if got := Normalize(want); got != want {
+		t.Fatalf("Normalize must be idempotent, got %q", got)
+	}
+	if got := Normalize(" \n\n"); got != "" {
+		t.Fatalf("blank input should normalize to empty, got %q", got)
+	}
+}
+
+func TestNormalize_HardBreaks(t *testing.T) {
+	in := "First line  \nsecond line  \n\n## Heading  \nText  \n# Next"
+	want := "First line<br>\nsecond line\n\n## Heading\nText\n# Next\n"
+	if got := Normalize(in); got != want {
+		t.Fatalf("Normalize() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,20 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	linkRe     = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	emphasisRe = regexp.MustCompile("[*`]+|\\b_+|_+\\b")
+)
+
+// PlainText drops Markdown links, images and emphasis from a line such as a
+// heading and collapses whitespace. Underscores inside words, as in
+// identifiers like Avatar_Cap_A1, are kept.
+func PlainText(s string) string {
+	s = linkRe.ReplaceAllString(s, "$1")
+	s = emphasisRe.ReplaceAllString(s, "")
+	return strings.Join(strings.Fields(s), " ")
+}
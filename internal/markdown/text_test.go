@@ -0,0 +1,18 @@
+package markdown
+
+import "testing"
+
+func TestPlainText(t *testing.T) {
+	cases := map[string]string{
+		"**Using** [Entity](https://x.com/Entity) `API`": "Using Entity API",
+		"(Avatar_Cap_A1)":            "(Avatar_Cap_A1)",
+		"_emphasis_ and __strong__":  "emphasis and strong",
+		"![alt](a.png)  spaced\tout": "alt spaced out",
+		"Getting_Started with *Lua*": "Getting_Started with Lua",
+	}
+	for in, want := range cases {
+		if got := PlainText(in); got != want {
+			t.Errorf("PlainText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// JSON-RPC 2.0 error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// isNotification reports whether the request expects no response.
+func (r *request) isNotification() bool { return len(r.ID) == 0 }
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message) }
+
+func errorf(code int, format string, args ...any) *rpcError {
+	return &rpcError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// handlerFunc handles one method call and returns its result or an error.
+// Returning an *rpcError controls the error code; other errors map to
+// codeInternalError.
+type handlerFunc func(ctx context.Context, params json.RawMessage) (any, error)
+
+// serveStream reads newline-delimited JSON-RPC messages from r and writes
+// responses to w until r is exhausted or ctx is cancelled.
+func serveStream(ctx context.Context, r io.Reader, w io.Writer, dispatch func(string) (handlerFunc, bool)) error {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	write := func(resp response) error {
+		mu.Lock()
+		defer mu.Unlock()
+		resp.JSONRPC = "2.0"
+		if resp.ID == nil {
+			resp.ID = json.RawMessage("null")
+		}
+		return enc.Encode(resp)
+	}
+
+	br := bufio.NewReader(r)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if werr := handleLine(ctx, line, dispatch, write); werr != nil {
+				return werr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func handleLine(ctx context.Context, line []byte, dispatch func(string) (handlerFunc, bool), write func(response) error) error {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return write(response{Error: errorf(codeParseError, "parse error: %v", err)})
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if req.isNotification() {
+			return nil
+		}
+		return write(response{ID: req.ID, Error: errorf(codeInvalidRequest, "invalid request")})
+	}
+
+	h, ok := dispatch(req.Method)
+	if !ok {
+		if req.isNotification() {
+			return nil
+		}
+		return write(response{ID: req.ID, Error: errorf(codeMethodNotFound, "method not found: %s", req.Method)})
+	}
+	result, err := h(ctx, req.Params)
+	if req.isNotification() {
+		return nil
+	}
+	if err != nil {
+		var rerr *rpcError
+		if !errors.As(err, &rerr) {
+			rerr = errorf(codeInternalError, "%v", err)
+		}
+		return write(response{ID: req.ID, Error: rerr})
+	}
+	if result == nil {
+		result = struct{}{}
+	}
+	return write(response{ID: req.ID, Result: result})
+}
@@ -0,0 +1,379 @@
+// Package mcp exposes the crawled documentation corpus to coding assistants
+// over the Model Context Protocol (JSON-RPC 2.0 on newline-delimited stdio).
+// Every document is published as a resource, and the search_docs,
+// get_api_class and list_sections tools answer structured queries.
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/apiref"
+	"maplestory-world-llms-txt/internal/corpus"
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/markdown"
+)
+
+// protocolVersions lists supported MCP revisions, newest first.
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// resourcePageSize bounds resources/list responses; clients page with the
+// returned cursor.
+const resourcePageSize = 200
+
+// Server answers MCP requests from a corpus.
+type Server struct {
+	Name    string
+	Version string
+	corpus  *corpus.Corpus
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithImplementation sets the server name and version reported on initialize.
+func WithImplementation(name, version string) Option {
+	return func(s *Server) {
+		if name != "" {
+			s.Name = name
+		}
+		if version != "" {
+			s.Version = version
+		}
+	}
+}
+
+// NewServer constructs a Server backed by c.
+func NewServer(c *corpus.Corpus, opts ...Option) *Server {
+	s := &Server{Name: "maplestory-worlds-docs", Version: "0.1.0", corpus: c}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
+}
+
+// Serve handles requests read from r, writing responses to w, until r reaches
+// EOF or ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	return serveStream(ctx, r, w, s.handler)
+}
+
+func (s *Server) handler(method string) (handlerFunc, bool) {
+	switch method {
+	case "initialize":
+		return s.initialize, true
+	case "ping", "notifications/initialized", "notifications/cancelled":
+		return func(context.Context, json.RawMessage) (any, error) { return nil, nil }, true
+	case "resources/list":
+		return s.listResources, true
+	case "resources/read":
+		return s.readResource, true
+	case "tools/list":
+		return s.listTools, true
+	case "tools/call":
+		return s.callTool, true
+	}
+	return nil, false
+}
+
+func (s *Server) initialize(_ context.Context, params json.RawMessage) (any, error) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	version := protocolVersions[0]
+	for _, v := range protocolVersions {
+		if v == p.ProtocolVersion {
+			version = v
+		}
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities": map[string]any{
+			"resources": map[string]any{},
+			"tools":     map[string]any{},
+		},
+		"serverInfo": map[string]any{"name": s.Name, "version": s.Version},
+		"instructions": "MapleStory Worlds creator documentation. Use search_docs to find pages, " +
+			"read them as resources, and get_api_class for component properties, methods and events.",
+	}, nil
+}
+
+type resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType"`
+}
+
+func (s *Server) listResources(_ context.Context, params json.RawMessage) (any, error) {
+	var p struct {
+		Cursor string `json:"cursor"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	start := 0
+	if p.Cursor != "" {
+		n, err := strconv.Atoi(p.Cursor)
+		if err != nil || n < 0 || n > len(s.corpus.Docs) {
+			return nil, errorf(codeInvalidParams, "invalid cursor %q", p.Cursor)
+		}
+		start = n
+	}
+	end := min(start+resourcePageSize, len(s.corpus.Docs))
+
+	list := make([]resource, 0, end-start)
+	for _, d := range s.corpus.Docs[start:end] {
+		list = append(list, resource{
+			URI:         corpus.URI(d),
+			Name:        d.Title,
+			Title:       d.Title,
+			Description: fmt.Sprintf("%s documentation (%s): %s", d.Kind, d.Lang, d.URL),
+			MimeType:    "text/markdown",
+		})
+	}
+	result := map[string]any{"resources": list}
+	if end < len(s.corpus.Docs) {
+		result["nextCursor"] = strconv.Itoa(end)
+	}
+	return result, nil
+}
+
+func (s *Server) readResource(_ context.Context, params json.RawMessage) (any, error) {
+	var p struct {
+		URI string `json:"uri"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	d, ok := s.doc(p.URI)
+	if !ok {
+		return nil, errorf(codeInvalidParams, "resource not found: %s", p.URI)
+	}
+	return map[string]any{
+		"contents": []map[string]any{{
+			"uri":      p.URI,
+			"mimeType": "text/markdown",
+			"text":     d.Markdown,
+		}},
+	}, nil
+}
+
+// tool describes a callable tool and its JSON Schema input.
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	call        func(args json.RawMessage) (string, error)
+}
+
+func (s *Server) tools() []tool {
+	langProp := map[string]any{"type": "string", "description": `Document language, e.g. "en" or "ko". Empty searches all languages.`}
+	return []tool{
+		{
+			Name:        "search_docs",
+			Description: "Full-text search (BM25) over the MapleStory Worlds documentation. Returns ranked titles, resource URIs, source URLs and snippets.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"query": map[string]any{"type": "string", "description": "Search terms; English or Korean."},
+					"lang":  langProp,
+					"limit": map[string]any{"type": "integer", "description": "Maximum number of results (default 10).", "minimum": 1},
+				},
+				"required": []string{"query"},
+			},
+			call: s.searchDocs,
+		},
+		{
+			Name:        "get_api_class",
+			Description: "Returns a component or class from the API reference as JSON: description, properties, methods (with parameters) and events, including badges such as ReadOnly or Sync and the class each inherited member comes from.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":              map[string]any{"type": "string", "description": "Class name, e.g. AIChaseComponent (case-insensitive)."},
+					"lang":              langProp,
+					"include_inherited": map[string]any{"type": "boolean", "description": "Include inherited members (default true)."},
+				},
+				"required": []string{"name"},
+			},
+			call: s.getAPIClass,
+		},
+		{
+			Name:        "list_sections",
+			Description: "Without uri, lists the documents of the corpus grouped by kind. With uri, lists the heading outline of that document.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"lang": langProp,
+					"uri":  map[string]any{"type": "string", "description": "Resource URI of a document."},
+				},
+			},
+			call: s.listSections,
+		},
+	}
+}
+
+func (s *Server) listTools(context.Context, json.RawMessage) (any, error) {
+	return map[string]any{"tools": s.tools()}, nil
+}
+
+func (s *Server) callTool(_ context.Context, params json.RawMessage) (any, error) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	for _, t := range s.tools() {
+		if t.Name != p.Name {
+			continue
+		}
+		text, err := t.call(p.Arguments)
+		if err != nil {
+			// Tool failures are reported in the result so the model can react.
+			return toolResult(err.Error(), true), nil
+		}
+		return toolResult(text, false), nil
+	}
+	return nil, errorf(codeInvalidParams, "unknown tool: %s", p.Name)
+}
+
+func toolResult(text string, isError bool) map[string]any {
+	return map[string]any{
+		"content": []map[string]any{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+}
+
+func (s *Server) searchDocs(args json.RawMessage) (string, error) {
+	var a struct {
+		Query string `json:"query"`
+		Lang  string `json:"lang"`
+		Limit int    `json:"limit"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(a.Query) == "" {
+		return "", fmt.Errorf("query is required")
+	}
+	if a.Limit <= 0 {
+		a.Limit = 10
+	}
+	results := s.corpus.Search(a.Query, a.Lang, a.Limit)
+	if len(results) == 0 {
+		return "No results.", nil
+	}
+	var b strings.Builder
+	for i, r := range results {
+		fmt.Fprintf(&b, "%d. %s\n   uri: %s%s\n   url: %s\n   %s\n", i+1, r.Title, corpus.URIScheme, r.ID, r.URL, r.Snippet)
+	}
+	return b.String(), nil
+}
+
+func (s *Server) getAPIClass(args json.RawMessage) (string, error) {
+	var a struct {
+		Name             string `json:"name"`
+		Lang             string `json:"lang"`
+		IncludeInherited *bool  `json:"include_inherited"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	cls, ok := s.corpus.Class(a.Lang, a.Name)
+	if !ok {
+		return "", fmt.Errorf("class not found: %s", a.Name)
+	}
+	out := *cls
+	if a.IncludeInherited != nil && !*a.IncludeInherited {
+		out.Properties = apiref.Own(cls.Properties)
+		out.Methods = apiref.Own(cls.Methods)
+		out.Events = apiref.Own(cls.Events)
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (s *Server) listSections(args json.RawMessage) (string, error) {
+	var a struct {
+		Lang string `json:"lang"`
+		URI  string `json:"uri"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if a.URI != "" {
+		d, ok := s.doc(a.URI)
+		if !ok {
+			return "", fmt.Errorf("resource not found: %s", a.URI)
+		}
+		fmt.Fprintf(&b, "%s (%s)\n", d.Title, a.URI)
+		for _, blk := range markdown.Parse(d.Markdown) {
+			if blk.Kind == markdown.Heading {
+				fmt.Fprintf(&b, "%s- %s\n", strings.Repeat("  ", blk.Level-1), blk.Title)
+			}
+		}
+		return b.String(), nil
+	}
+	for _, kind := range []document.Kind{document.KindReference, document.KindAPI} {
+		header := false
+		for _, d := range s.corpus.Docs {
+			if d.Kind != kind || (a.Lang != "" && d.Lang != a.Lang) {
+				continue
+			}
+			if !header {
+				fmt.Fprintf(&b, "## %s\n", kind)
+				header = true
+			}
+			fmt.Fprintf(&b, "- %s (%s)\n", d.Title, corpus.URI(d))
+		}
+	}
+	if b.Len() == 0 {
+		return "No documents.", nil
+	}
+	return b.String(), nil
+}
+
+// doc resolves a resource URI to its document.
+func (s *Server) doc(uri string) (document.Document, bool) {
+	id, ok := corpus.IDFromURI(uri)
+	if !ok {
+		return document.Document{}, false
+	}
+	return s.corpus.Doc(id)
+}
+
+func decodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return errorf(codeInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
+
+func decodeArgs(args json.RawMessage, v any) error {
+	if len(args) == 0 || string(args) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(args, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/corpus"
+	"maplestory-world-llms-txt/internal/document"
+)
+
+func testServer() *Server {
+	docs := []document.Document{
+		{Title: "Workspace", URL: "https://maplestoryworlds-creators.nexon.com/en/docs/?postId=472",
+			Markdown: "# Workspace\n\n## Searching Workspace\n\nUse the search box."},
+		{Title: "AIChaseComponent", URL: "https://maplestoryworlds-creators.nexon.com/en/apiReference/Components/AIChaseComponent",
+			Markdown: "# AIChaseComponent\n\nChases players.\n\n# Properties\n\n| float DetectionRange |\n| --- |\n| Range. |\n\n" +
+				"##### inherited from Component:\n\n| boolean Enable [Sync] |\n| --- |\n| Enabled. |\n"},
+	}
+	return NewServer(corpus.New(docs))
+}
+
+// roundTrip sends newline-delimited requests and decodes every response line.
+func roundTrip(t *testing.T, s *Server, reqs ...string) []map[string]any {
+	t.Helper()
+	var out bytes.Buffer
+	if err := s.Serve(context.Background(), strings.NewReader(strings.Join(reqs, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	var resps []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("decode %q: %v", line, err)
+		}
+		resps = append(resps, m)
+	}
+	return resps
+}
+
+func TestServe_InitializeAndNotifications(t *testing.T) {
+	resps := roundTrip(t, testServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"nope"}`,
+		`not json`,
+	)
+	if len(resps) != 4 {
+		t.Fatalf("expected 4 responses (notification has none), got %d: %v", len(resps), resps)
+	}
+	result := resps[0]["result"].(map[string]any)
+	if result["protocolVersion"] != "2024-11-05" {
+		t.Fatalf("expected negotiated version, got %v", result["protocolVersion"])
+	}
+	if _, ok := resps[1]["result"]; !ok {
+		t.Fatalf("ping should return an empty result: %v", resps[1])
+	}
+	if code := resps[2]["error"].(map[string]any)["code"].(float64); code != codeMethodNotFound {
+		t.Fatalf("expected method not found, got %v", code)
+	}
+	if code := resps[3]["error"].(map[string]any)["code"].(float64); code != codeParseError {
+		t.Fatalf("expected parse error, got %v", code)
+	}
+}
+
+func TestServe_Resources(t *testing.T) {
+	resps := roundTrip(t, testServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"mswdocs://en/docs/472"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"mswdocs://en/docs/0"}}`,
+	)
+	list := resps[0]["result"].(map[string]any)["resources"].([]any)
+	if len(list) != 2 || list[0].(map[string]any)["uri"] != "mswdocs://en/docs/472" {
+		t.Fatalf("unexpected resources: %v", list)
+	}
+	contents := resps[1]["result"].(map[string]any)["contents"].([]any)
+	if !strings.HasPrefix(contents[0].(map[string]any)["text"].(string), "# Workspace") {
+		t.Fatalf("unexpected contents: %v", contents)
+	}
+	if _, ok := resps[2]["error"]; !ok {
+		t.Fatalf("expected error for unknown resource: %v", resps[2])
+	}
+}
+
+func toolText(t *testing.T, resp map[string]any) (string, bool) {
+	t.Helper()
+	result := resp["result"].(map[string]any)
+	content := result["content"].([]any)
+	return content[0].(map[string]any)["text"].(string), result["isError"].(bool)
+}
+
+func TestServe_Tools(t *testing.T) {
+	resps := roundTrip(t, testServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"search_docs","arguments":{"query":"DetectionRange"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"get_api_class","arguments":{"name":"aichasecomponent","include_inherited":false}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"list_sections","arguments":{"uri":"mswdocs://en/docs/472"}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"get_api_class","arguments":{"name":"Missing"}}}`,
+	)
+	if tools := resps[0]["result"].(map[string]any)["tools"].([]any); len(tools) != 3 {
+		t.Fatalf("expected 3 tools, got %d", len(tools))
+	}
+	if text, isErr := toolText(t, resps[1]); isErr || !strings.Contains(text, "AIChaseComponent") {
+		t.Fatalf("unexpected search result: %q", text)
+	}
+
+	text, isErr := toolText(t, resps[2])
+	if isErr {
+		t.Fatalf("get_api_class failed: %s", text)
+	}
+	var cls struct {
+		Name       string `json:"name"`
+		Properties []struct {
+			Name string `json:"name"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(text), &cls); err != nil {
+		t.Fatalf("decode class: %v", err)
+	}
+	if cls.Name != "AIChaseComponent" || len(cls.Properties) != 1 || cls.Properties[0].Name != "DetectionRange" {
+		t.Fatalf("unexpected class: %+v", cls)
+	}
+
+	if text, _ := toolText(t, resps[3]); !strings.Contains(text, "  - Searching Workspace") {
+		t.Fatalf("unexpected outline: %q", text)
+	}
+	if _, isErr := toolText(t, resps[4]); !isErr {
+		t.Fatalf("expected tool error for unknown class")
+	}
+}
@@ -0,0 +1,200 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/convert"
+	"maplestory-world-llms-txt/internal/document"
+)
+
+// maxImageBytes bounds the size of one downloaded image.
+const maxImageBytes = 20 << 20
+
+// Image is the content of an image embedded in a publication.
+type Image struct {
+	MediaType string
+	Data      []byte
+}
+
+// Images maps absolute image URLs to their content.
+type Images map[string]Image
+
+// Assets reads the images of a corpus from a local cache directory holding
+// one file per image URL, named after the URL's SHA-256. With WithFetch,
+// images missing from the cache are downloaded into it first.
+type Assets struct {
+	dir    string
+	client *http.Client
+}
+
+// AssetOption configures Assets.
+type AssetOption func(*Assets)
+
+// WithFetch downloads images missing from the cache with client, or with
+// http.DefaultClient when client is nil.
+func WithFetch(client *http.Client) AssetOption {
+	return func(a *Assets) {
+		if client == nil {
+			client = http.DefaultClient
+		}
+		a.client = client
+	}
+}
+
+// NewAssets returns the asset cache in dir.
+func NewAssets(dir string, opts ...AssetOption) *Assets {
+	a := &Assets{dir: dir}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(a)
+		}
+	}
+	return a
+}
+
+// markdownImageRe matches the destination of Markdown images.
+var markdownImageRe = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)`)
+
+// Load returns the images of docs found in the cache, downloading missing
+// ones when fetching is enabled, and the URLs it could not provide. An image
+// that fails to download is reported as missing rather than as an error.
+func (a *Assets) Load(ctx context.Context, docs []document.Document) (Images, []string, error) {
+	images := make(Images)
+	var missing []string
+	for _, src := range imageSources(docs) {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		img, err := a.get(ctx, src)
+		switch {
+		case err == nil:
+			images[src] = img
+		case ctx.Err() != nil:
+			return nil, nil, ctx.Err()
+		default:
+			missing = append(missing, src)
+		}
+	}
+	return images, missing, nil
+}
+
+// imageSources returns the distinct http(s) image URLs of docs in order:
+// those behind "[Image: ...]" placeholders and Markdown images.
+func imageSources(docs []document.Document) []string {
+	seen := make(map[string]bool)
+	var out []string
+	add := func(src string) {
+		if u, err := url.Parse(src); err == nil && (u.Scheme == "http" || u.Scheme == "https") && !seen[src] {
+			seen[src] = true
+			out = append(out, src)
+		}
+	}
+	for _, d := range docs {
+		if imgs, err := convert.Images(d.HTML, d.URL); err == nil {
+			for _, img := range imgs {
+				add(img.Src)
+			}
+		}
+		base, err := url.Parse(d.URL)
+		if err != nil {
+			continue
+		}
+		for _, m := range markdownImageRe.FindAllStringSubmatch(d.Markdown, -1) {
+			if u, err := base.Parse(strings.ReplaceAll(m[1], "&amp;", "&")); err == nil {
+				add(u.String())
+			}
+		}
+	}
+	return out
+}
+
+func (a *Assets) path(src string) string {
+	sum := sha256.Sum256([]byte(src))
+	return filepath.Join(a.dir, hex.EncodeToString(sum[:]))
+}
+
+func (a *Assets) get(ctx context.Context, src string) (Image, error) {
+	path := a.path(src)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && a.client != nil {
+		data, err = a.fetch(ctx, src, path)
+	}
+	if err != nil {
+		return Image{}, err
+	}
+	mt := mediaType(data)
+	if mt == "" {
+		return Image{}, fmt.Errorf("%s: not an image", src)
+	}
+	return Image{MediaType: mt, Data: data}, nil
+}
+
+// fetch downloads src into the cache file path.
+func (a *Assets) fetch(ctx context.Context, src, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", src, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageBytes {
+		return nil, fmt.Errorf("%s: larger than %d bytes", src, maxImageBytes)
+	}
+	if mediaType(data) == "" {
+		return nil, fmt.Errorf("%s: not an image", src)
+	}
+	if err := os.MkdirAll(a.dir, 0o755); err != nil {
+		return nil, err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return nil, err
+	}
+	return data, os.Rename(tmp, path)
+}
+
+// mediaType sniffs the image type of data, or returns "" when it is not an
+// image.
+func mediaType(data []byte) string {
+	mt := http.DetectContentType(data)
+	if strings.HasPrefix(mt, "image/") {
+		return mt
+	}
+	head := data[:min(len(data), 512)]
+	if bytes.Contains(head, []byte("<svg")) {
+		return "image/svg+xml"
+	}
+	return ""
+}
+
+// imageExt is the file extension of each image type EPUB readers support.
+var imageExt = map[string]string{
+	"image/gif":     ".gif",
+	"image/jpeg":    ".jpg",
+	"image/png":     ".png",
+	"image/svg+xml": ".svg",
+	"image/webp":    ".webp",
+}
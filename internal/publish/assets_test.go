@@ -0,0 +1,64 @@
+package publish
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/document"
+)
+
+func TestAssetsLoad(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/shot.png":
+			w.Write(pngData)
+		case "/page.html":
+			w.Write([]byte("<!DOCTYPE html><p>not an image</p>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	docs := []document.Document{{
+		URL:      srv.URL + "/docs/1",
+		HTML:     `<h2>Setup</h2><p><img src="/shot.png" alt="setup"></p>`,
+		Markdown: "![gone](/gone.png) ![page](/page.html) ![again](" + srv.URL + "/shot.png)",
+	}}
+	dir := t.TempDir()
+
+	// Without fetching, an empty cache provides nothing
+	images, missing, err := NewAssets(dir).Load(context.Background(), docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != 0 || len(missing) != 3 || requests != 0 {
+		t.Fatalf("got %d images, missing %v, %d requests", len(images), missing, requests)
+	}
+
+	images, missing, err = NewAssets(dir, WithFetch(srv.Client())).Load(context.Background(), docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shot := srv.URL + "/shot.png"
+	if img, ok := images[shot]; !ok || img.MediaType != "image/png" || len(images) != 1 {
+		t.Fatalf("expected %s as PNG, got %v", shot, images)
+	}
+	if len(missing) != 2 {
+		t.Errorf("expected the missing page and non-image as missing, got %v", missing)
+	}
+	if _, err := os.Stat(NewAssets(dir).path(shot)); err != nil {
+		t.Errorf("the image must be cached: %v", err)
+	}
+
+	// The cache now serves the image offline
+	srv.Close()
+	images, _, err = NewAssets(dir).Load(context.Background(), docs)
+	if err != nil || len(images) != 1 {
+		t.Fatalf("expected the cached image, got %v, %v", images, err)
+	}
+}
@@ -0,0 +1,323 @@
+// Package publish writes the converted corpus in reading formats: an EPUB 3
+// book per language whose navigation matches the site tree, and a single-file
+// HTML page with a sidebar and a search box. Both are built in pure Go from
+// the crawl artifacts. Links between documents point into the publication.
+// Images given with WithImages, e.g. from an Assets cache, are embedded: as
+// files of the EPUB and as data URIs in HTML, which is then self-contained.
+// Others stay on the documentation CDN, as links in the EPUB (which may not
+// embed remote images) and as <img> elements in HTML.
+package publish
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"maplestory-world-llms-txt/internal/assemble"
+	"maplestory-world-llms-txt/internal/convert"
+	"maplestory-world-llms-txt/internal/document"
+)
+
+// titles are the default book titles per language.
+var titles = map[string]string{
+	"en": "MapleStory Worlds Documentation",
+	"ko": "메이플스토리 월드 문서",
+}
+
+// kindLabels name the top-level groups of a book holding both guides and the
+// API reference.
+var kindLabels = map[string]map[document.Kind]string{
+	"en": {document.KindReference: "Guides", document.KindAPI: "API Reference"},
+	"ko": {document.KindReference: "가이드", document.KindAPI: "API 레퍼런스"},
+}
+
+// epoch is the modification time of a book whose documents carry no fetch
+// time; the earliest time a ZIP file can record.
+var epoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Book is the publication of one language.
+type Book struct {
+	Lang  string
+	Title string
+	// Modified is written into the EPUB metadata and the ZIP entries. It
+	// defaults to the latest fetch time of the documents, so regenerating
+	// from the same corpus yields the same bytes.
+	Modified time.Time
+
+	parts    []assemble.Part
+	headings []assemble.Heading
+	// byID maps document IDs to their index in parts.
+	byID map[string]int
+	// roots holds the anchor of every document's title heading.
+	roots []string
+	// images holds the images to embed by URL.
+	images Images
+}
+
+// Option configures a Book.
+type Option func(*Book)
+
+// WithTitle overrides the default title of the book.
+func WithTitle(title string) Option {
+	return func(b *Book) {
+		if title != "" {
+			b.Title = title
+		}
+	}
+}
+
+// WithModified overrides the modification time of the book.
+func WithModified(t time.Time) Option {
+	return func(b *Book) {
+		if !t.IsZero() {
+			b.Modified = t.UTC()
+		}
+	}
+}
+
+// WithImages embeds images instead of referencing them on the CDN. The
+// "[Image: ...]" placeholders of the documents turn back into the pictures
+// they describe when their source is among images.
+func WithImages(images Images) Option {
+	return func(b *Book) {
+		b.images = images
+	}
+}
+
+// NewBook builds the book of one language from its documents, in reading
+// order. When the parts hold both guides and API pages, each kind becomes a
+// top-level group of the navigation.
+func NewBook(lang string, parts []assemble.Part, opts ...Option) *Book {
+	b := &Book{Lang: lang, Title: titles[lang], Modified: epoch, byID: make(map[string]int)}
+	if b.Title == "" {
+		b.Title = titles["en"]
+	}
+	kinds := make(map[document.Kind]bool)
+	for _, p := range parts {
+		kinds[p.Doc.Kind] = true
+		if t := p.Doc.Fetch.FetchedAt; t.After(b.Modified) {
+			b.Modified = t.UTC().Truncate(time.Second)
+		}
+	}
+	labels := kindLabels[lang]
+	if labels == nil {
+		labels = kindLabels["en"]
+	}
+	b.parts = make([]assemble.Part, len(parts))
+	for i, p := range parts {
+		if len(kinds) > 1 {
+			p.Doc.Breadcrumb = append([]string{labels[p.Doc.Kind]}, p.Doc.Breadcrumb...)
+		}
+		b.parts[i] = p
+		b.byID[p.Doc.ID] = i
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(b)
+		}
+	}
+	if len(b.images) > 0 {
+		for i := range b.parts {
+			b.parts[i].Markdown = b.restoreImages(b.parts[i])
+		}
+	}
+
+	b.headings = assemble.Outline(b.parts)
+	b.roots = make([]string, len(parts))
+	for _, h := range b.headings {
+		if h.Doc >= 0 && h.Depth == 0 {
+			b.roots[h.Doc] = h.Anchor
+		}
+	}
+	return b
+}
+
+// target returns the document a link found in document doc points to and
+// the link's fragment, or -1 when it leads outside the book.
+func (b *Book) target(doc int, href string) (int, string) {
+	base, err := url.Parse(b.parts[doc].Doc.URL)
+	if err != nil {
+		return -1, ""
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return -1, ""
+	}
+	abs := base.ResolveReference(ref)
+	if abs.Scheme != "http" && abs.Scheme != "https" {
+		return -1, ""
+	}
+	// Pages also link to the site under its former host, mod-developers
+	if abs.Host != base.Host && !strings.HasSuffix(abs.Hostname(), ".nexon.com") {
+		return -1, ""
+	}
+	frag := abs.Fragment
+	abs.Fragment = ""
+	id := document.IDOf(abs.String())
+	if i, ok := b.byID[id]; ok {
+		return i, frag
+	}
+	// Links such as /docs?postId=54 omit the language
+	if i, ok := b.byID[b.Lang+"/"+id]; ok {
+		return i, frag
+	}
+	return -1, ""
+}
+
+// linker returns the link rewriter for document doc: links to a document of
+// the book go through local, others are made absolute.
+func (b *Book) linker(doc int, local func(target int) string) func(string) string {
+	return func(href string) string {
+		if strings.HasPrefix(href, "#") {
+			return href
+		}
+		if t, _ := b.target(doc, href); t >= 0 {
+			return local(t)
+		}
+		if base, err := url.Parse(b.parts[doc].Doc.URL); err == nil {
+			if ref, err := url.Parse(href); err == nil {
+				return base.ResolveReference(ref).String()
+			}
+		}
+		return href
+	}
+}
+
+// restoreImages returns the Markdown of p with the placeholders of embedded
+// images replaced by Markdown images, keeping the placeholder as alt text.
+func (b *Book) restoreImages(p assemble.Part) string {
+	imgs, err := convert.Images(p.Doc.HTML, p.Doc.URL)
+	if err != nil {
+		return p.Markdown
+	}
+	md := p.Markdown
+	for _, img := range imgs {
+		if _, ok := b.images[img.Src]; !ok || strings.ContainsAny(img.Src, " ()") {
+			continue
+		}
+		alt := strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(img.Placeholder, "[Image"), "]"), ": ")
+		alt = strings.NewReplacer("[", "(", "]", ")").Replace(alt)
+		image := "![" + alt + "](" + img.Src + ")"
+		// mdream escapes the brackets of the placeholder
+		inner := strings.TrimSuffix(strings.TrimPrefix(img.Placeholder, "["), "]")
+		for _, text := range []string{`\[` + inner + `\]`, img.Placeholder} {
+			if strings.Contains(md, text) {
+				md = strings.Replace(md, text, image, 1)
+				break
+			}
+		}
+	}
+	return md
+}
+
+// embedder returns the image resolver of document doc: sources of embedded
+// images go through ref, which may decline them.
+func (b *Book) embedder(doc int, ref func(src string, img Image) (string, bool)) func(string) (string, bool) {
+	if len(b.images) == 0 {
+		return nil
+	}
+	base, err := url.Parse(b.parts[doc].Doc.URL)
+	return func(src string) (string, bool) {
+		if err == nil {
+			if u, err := base.Parse(src); err == nil {
+				src = u.String()
+			}
+		}
+		img, ok := b.images[src]
+		if !ok {
+			return "", false
+		}
+		return ref(src, img)
+	}
+}
+
+// dataURI returns img as a data URI.
+func dataURI(_ string, img Image) (string, bool) {
+	return "data:" + img.MediaType + ";base64," + base64.StdEncoding.EncodeToString(img.Data), true
+}
+
+// imageFile returns the name of the EPUB file holding the image at src, or
+// false when EPUB readers do not support its type.
+func imageFile(src string, img Image) (string, bool) {
+	ext, ok := imageExt[img.MediaType]
+	if !ok {
+		return "", false
+	}
+	sum := sha256.Sum256([]byte(src))
+	return "images/" + hex.EncodeToString(sum[:8]) + ext, true
+}
+
+// writeSection writes a heading and its body as a <section> with the given
+// id, with the heading at level.
+func writeSection(w *strings.Builder, id string, h assemble.Heading, level int, path string, x *xhtml) {
+	level = min(max(level, 1), 6)
+	fmt.Fprintf(w, "<section id=\"%s\"", html.EscapeString(id))
+	if path != "" {
+		fmt.Fprintf(w, " data-path=\"%s\"", html.EscapeString(path))
+	}
+	fmt.Fprintf(w, ">\n<h%d>%s</h%d>\n", level, x.inline(h.Title), level)
+	x.blocks(w, h.Body)
+	w.WriteString("</section>\n")
+}
+
+// xmlID returns an id derived from a heading anchor that is a valid XML
+// name, as EPUB requires: anchors contain "/" and may start with a digit.
+// Slugs never contain "--", so it stands in for "/" unambiguously.
+func xmlID(anchor string) string {
+	return "s-" + strings.ReplaceAll(anchor, "/", "--")
+}
+
+// navItem is an entry of a navigation list.
+type navItem struct {
+	level int
+	title string
+	// href is empty for groups, which are written as <span>.
+	href string
+}
+
+// navItems returns the nav groups and documents of the book, with href
+// giving the link of each document.
+func (b *Book) navItems(href func(doc int) string) []navItem {
+	var items []navItem
+	for _, h := range b.headings {
+		switch {
+		case h.Doc < 0:
+			items = append(items, navItem{level: h.Level, title: h.Title})
+		case h.Depth == 0:
+			items = append(items, navItem{level: h.Level, title: h.Title, href: href(h.Doc)})
+		}
+	}
+	return items
+}
+
+// writeNavList writes items as nested ordered lists following their levels.
+func writeNavList(w *strings.Builder, items []navItem) {
+	var list func(i int) int
+	list = func(i int) int {
+		level := items[i].level
+		w.WriteString("<ol>\n")
+		for i < len(items) && items[i].level >= level {
+			it := items[i]
+			if it.href != "" {
+				fmt.Fprintf(w, "<li><a href=\"%s\">%s</a>", html.EscapeString(it.href), html.EscapeString(it.title))
+			} else {
+				fmt.Fprintf(w, "<li><span>%s</span>", html.EscapeString(it.title))
+			}
+			if i++; i < len(items) && items[i].level > level {
+				w.WriteString("\n")
+				i = list(i)
+			}
+			w.WriteString("</li>\n")
+		}
+		w.WriteString("</ol>\n")
+		return i
+	}
+	for i := 0; i < len(items); {
+		i = list(i)
+	}
+}
@@ -0,0 +1,102 @@
+package publish
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+const bundleCSS = `* { box-sizing: border-box; }
+body { margin: 0; font-family: system-ui, sans-serif; line-height: 1.5; color: #222; }
+#sidebar { position: fixed; top: 0; bottom: 0; left: 0; width: 320px; overflow-y: auto; padding: 1em; background: #f7f7f7; border-right: 1px solid #ddd; }
+#sidebar input { width: 100%; padding: 0.4em; margin-bottom: 0.5em; font-size: 1em; }
+#sidebar ol { list-style: none; padding-left: 1em; margin: 0; }
+#sidebar > nav > ol, #results { padding-left: 0; }
+#sidebar span { font-weight: bold; }
+#sidebar a { color: #0645ad; text-decoration: none; }
+main { margin-left: 320px; padding: 1em 2em; max-width: 60em; }
+pre { white-space: pre-wrap; background: #f5f5f5; padding: 0.5em; overflow-x: auto; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; vertical-align: top; }
+img { max-width: 100%; }
+@media (max-width: 800px) { #sidebar { position: static; width: auto; } main { margin-left: 0; } }
+`
+
+// bundleJS filters the sections by the search terms, listing title matches
+// before text matches, and swaps the results in for the table of contents.
+const bundleJS = `(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("results");
+  var toc = document.getElementById("toc");
+  var sections = Array.prototype.slice.call(document.querySelectorAll("main section[id]"));
+  var texts = null;
+  input.addEventListener("input", function () {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    results.textContent = "";
+    results.hidden = terms.length === 0;
+    toc.hidden = terms.length > 0;
+    if (!terms.length) return;
+    if (!texts) texts = sections.map(function (s) { return s.textContent.toLowerCase(); });
+    var titled = [], other = [];
+    sections.forEach(function (s, i) {
+      var all = terms.every(function (t) { return texts[i].indexOf(t) >= 0; });
+      if (!all) return;
+      var title = s.firstElementChild.textContent.toLowerCase();
+      (terms.every(function (t) { return title.indexOf(t) >= 0; }) ? titled : other).push(s);
+    });
+    titled.concat(other).slice(0, 100).forEach(function (s) {
+      var li = document.createElement("li"), a = document.createElement("a");
+      a.href = "#" + s.id;
+      a.textContent = s.getAttribute("data-path");
+      li.appendChild(a);
+      results.appendChild(li);
+    });
+  });
+})();
+`
+
+// WriteHTML writes the book as a single self-contained HTML page: a sidebar
+// with a search box and the table of contents, and every document with its
+// nav group headings. Styles and script are embedded; only images load from
+// the documentation CDN.
+func (b *Book) WriteHTML(w io.Writer) error {
+	var s strings.Builder
+	s.WriteString("<!DOCTYPE html>\n")
+	fmt.Fprintf(&s, "<html lang=\"%s\">\n<head>\n<meta charset=\"utf-8\"/>\n", html.EscapeString(b.Lang))
+	s.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\"/>\n")
+	fmt.Fprintf(&s, "<title>%s</title>\n<style>\n%s</style>\n</head>\n<body>\n", html.EscapeString(b.Title), bundleCSS)
+
+	placeholder := map[string]string{"en": "Search", "ko": "검색"}[b.Lang]
+	if placeholder == "" {
+		placeholder = "Search"
+	}
+	s.WriteString("<aside id=\"sidebar\">\n")
+	fmt.Fprintf(&s, "<input id=\"search\" type=\"search\" placeholder=\"%s\" autocomplete=\"off\"/>\n", placeholder)
+	s.WriteString("<ol id=\"results\" hidden=\"hidden\"></ol>\n<nav id=\"toc\">\n")
+	writeNavList(&s, b.navItems(func(doc int) string { return "#" + b.roots[doc] }))
+	s.WriteString("</nav>\n</aside>\n<main>\n")
+	fmt.Fprintf(&s, "<h1>%s</h1>\n", html.EscapeString(b.Title))
+
+	// Headings move down one level below the page title. A document's
+	// headings are contiguous, so its title is known for its sections.
+	var docTitle string
+	for _, h := range b.headings {
+		if h.Doc < 0 {
+			level := min(h.Level+1, 6)
+			fmt.Fprintf(&s, "<h%d class=\"group\">%s</h%d>\n", level, html.EscapeString(h.Title), level)
+			continue
+		}
+		path := h.Title
+		if h.Depth == 0 {
+			docTitle = h.Title
+		} else {
+			path = docTitle + " › " + h.Title
+		}
+		x := &xhtml{images: true, link: b.linker(h.Doc, func(t int) string { return "#" + b.roots[t] }), embed: b.embedder(h.Doc, dataURI)}
+		writeSection(&s, h.Anchor, h, h.Level+1, path, x)
+	}
+	s.WriteString("</main>\n<script>\n" + bundleJS + "</script>\n</body>\n</html>\n")
+	_, err := io.WriteString(w, s.String())
+	return err
+}
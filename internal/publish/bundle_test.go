@@ -0,0 +1,66 @@
+package publish
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestWriteHTML(t *testing.T) {
+	var b strings.Builder
+	if err := NewBook("en", sampleParts()).WriteHTML(&b); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"<title>MapleStory Worlds Documentation</title>",
+		`<input id="search" type="search" placeholder="Search" autocomplete="off"/>`,
+		"<nav id=\"toc\">\n<ol>\n<li><span>Guides</span>",
+		`<li><a href="#workspace">Workspace</a></li>`,
+		"<h2 class=\"group\">Guides</h2>\n<h3 class=\"group\">Maker</h3>\n<h4 class=\"group\">Basic Guide</h4>\n",
+		"<section id=\"workspace\" data-path=\"Workspace\">\n<h5>Workspace</h5>",
+		"<section id=\"workspace/panels\" data-path=\"Workspace › Panels\">\n<h6>Panels</h6>",
+		`<a href="#hierarchy">Hierarchy</a>`,
+		`<img src="https://cdn.example.com/tree.png" alt="tree" loading="lazy"/>`,
+		"<th>Name</th><th>Type</th>",
+		`querySelectorAll("main section[id]")`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in the page", want)
+		}
+	}
+	if strings.Contains(got, "<link") || strings.Contains(got, "<script src") {
+		t.Error("the page must not load local assets from other files")
+	}
+}
+
+func TestWriteHTML_SingleKindHasNoKindGroup(t *testing.T) {
+	var b strings.Builder
+	if err := NewBook("ko", sampleParts()[:2]).WriteHTML(&b); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	if strings.Contains(got, "Guides") || strings.Contains(got, "가이드") {
+		t.Errorf("a book of one kind must not group by kind")
+	}
+	if !strings.Contains(got, `<html lang="ko">`) || !strings.Contains(got, `placeholder="검색"`) ||
+		!strings.Contains(got, "<title>메이플스토리 월드 문서</title>") {
+		t.Errorf("expected Korean labels")
+	}
+}
+
+func TestWriteHTML_EmbedsImages(t *testing.T) {
+	parts, images := imageParts(t)
+	var b strings.Builder
+	if err := NewBook("en", parts, WithImages(images)).WriteHTML(&b); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	uri := "data:image/png;base64," + base64.StdEncoding.EncodeToString(pngData)
+	if n := strings.Count(got, `<img src="`+uri+`"`); n != 2 {
+		t.Errorf("expected 2 images as data URIs, got %d", n)
+	}
+	if strings.Contains(got, "cdn.example.com/tree.png") {
+		t.Error("embedded images must not load from the CDN")
+	}
+}
@@ -0,0 +1,164 @@
+package publish
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"fmt"
+	"html"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const epubCSS = `body { font-family: sans-serif; line-height: 1.5; }
+pre { white-space: pre-wrap; background: #f5f5f5; padding: 0.5em; }
+code { font-family: monospace; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; vertical-align: top; }
+a.image { font-style: italic; }
+img { max-width: 100%; }
+nav ol { list-style: none; }
+`
+
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="EPUB/package.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+// chapterFile returns the content document of document i.
+func chapterFile(i int) string { return fmt.Sprintf("doc%04d.xhtml", i) }
+
+// WriteEPUB writes the book as an EPUB 3 publication: one content document
+// per document, in reading order, and a navigation document whose table of
+// contents nests the documents under their nav groups. Heading levels restart
+// at h1 in every content document. Embedded images are stored under images/.
+func (b *Book) WriteEPUB(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	add := func(name string, method uint16, data string) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: b.Modified})
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(f, data)
+		return err
+	}
+
+	// The mimetype entry comes first and uncompressed so readers can sniff it
+	if err := add("mimetype", zip.Store, "application/epub+zip"); err != nil {
+		return err
+	}
+	// Chapters come first as they tell which images the book holds
+	images := make(map[string]Image)
+	chapters := make([]string, len(b.parts))
+	for i := range b.parts {
+		chapters[i] = b.chapter(i, images)
+	}
+	files := []struct{ name, data string }{
+		{"META-INF/container.xml", containerXML},
+		{"EPUB/package.opf", b.packageDocument(images)},
+		{"EPUB/nav.xhtml", b.navDocument()},
+		{"EPUB/style.css", epubCSS},
+	}
+	for i, c := range chapters {
+		files = append(files, struct{ name, data string }{"EPUB/" + chapterFile(i), c})
+	}
+	for _, f := range files {
+		if err := add(f.name, zip.Deflate, f.data); err != nil {
+			return err
+		}
+	}
+	// Images are compressed already
+	for _, name := range slices.Sorted(maps.Keys(images)) {
+		if err := add("EPUB/"+name, zip.Store, string(images[name].Data)); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// identifier returns a name-based UUID for the book, stable across builds of
+// the same language and title.
+func (b *Book) identifier() string {
+	sum := sha1.Sum([]byte("maplestory-world-llms-txt/" + b.Lang + "/" + b.Title))
+	sum[6] = sum[6]&0x0f | 0x50
+	sum[8] = sum[8]&0x3f | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// packageDocument returns the package document listing the chapters and
+// images, which map EPUB file names to their content.
+func (b *Book) packageDocument(images map[string]Image) string {
+	var s strings.Builder
+	s.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&s, `<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid" xml:lang="%s">`+"\n", html.EscapeString(b.Lang))
+	s.WriteString(`<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">` + "\n")
+	fmt.Fprintf(&s, "<dc:identifier id=\"uid\">%s</dc:identifier>\n", b.identifier())
+	fmt.Fprintf(&s, "<dc:title>%s</dc:title>\n", html.EscapeString(b.Title))
+	fmt.Fprintf(&s, "<dc:language>%s</dc:language>\n", html.EscapeString(b.Lang))
+	fmt.Fprintf(&s, "<meta property=\"dcterms:modified\">%s</meta>\n", b.Modified.Format("2006-01-02T15:04:05Z"))
+	s.WriteString("</metadata>\n<manifest>\n")
+	s.WriteString(`<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>` + "\n")
+	s.WriteString(`<item id="css" href="style.css" media-type="text/css"/>` + "\n")
+	for i := range b.parts {
+		fmt.Fprintf(&s, "<item id=\"doc%04d\" href=\"%s\" media-type=\"application/xhtml+xml\"/>\n", i, chapterFile(i))
+	}
+	for i, name := range slices.Sorted(maps.Keys(images)) {
+		fmt.Fprintf(&s, "<item id=\"img%04d\" href=\"%s\" media-type=\"%s\"/>\n", i, name, images[name].MediaType)
+	}
+	s.WriteString("</manifest>\n<spine>\n")
+	for i := range b.parts {
+		fmt.Fprintf(&s, "<itemref idref=\"doc%04d\"/>\n", i)
+	}
+	s.WriteString("</spine>\n</package>\n")
+	return s.String()
+}
+
+// xhtmlHead opens an XHTML content document.
+func (b *Book) xhtmlHead(s *strings.Builder, title string) {
+	s.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n<!DOCTYPE html>\n")
+	fmt.Fprintf(s, `<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="%[1]s" xml:lang="%[1]s">`+"\n", html.EscapeString(b.Lang))
+	fmt.Fprintf(s, "<head>\n<meta charset=\"utf-8\"/>\n<title>%s</title>\n<link rel=\"stylesheet\" type=\"text/css\" href=\"style.css\"/>\n</head>\n<body>\n", html.EscapeString(title))
+}
+
+func (b *Book) navDocument() string {
+	var s strings.Builder
+	b.xhtmlHead(&s, b.Title)
+	s.WriteString("<nav epub:type=\"toc\" id=\"toc\">\n")
+	fmt.Fprintf(&s, "<h1>%s</h1>\n", html.EscapeString(b.Title))
+	writeNavList(&s, b.navItems(chapterFile))
+	s.WriteString("</nav>\n</body>\n</html>\n")
+	return s.String()
+}
+
+// chapter returns the content document of document i, adding the images it
+// embeds to images by file name.
+func (b *Book) chapter(i int, images map[string]Image) string {
+	x := &xhtml{
+		link: b.linker(i, func(t int) string { return chapterFile(t) }),
+		embed: b.embedder(i, func(src string, img Image) (string, bool) {
+			name, ok := imageFile(src, img)
+			if ok {
+				images[name] = img
+			}
+			return name, ok
+		}),
+	}
+	var s strings.Builder
+	b.xhtmlHead(&s, b.parts[i].Doc.Title)
+	root := 0
+	for _, h := range b.headings {
+		if h.Doc != i {
+			continue
+		}
+		if h.Depth == 0 {
+			root = h.Level
+		}
+		writeSection(&s, xmlID(h.Anchor), h, h.Level-root+1, "", x)
+	}
+	s.WriteString("</body>\n</html>\n")
+	return s.String()
+}
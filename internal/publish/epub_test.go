@@ -0,0 +1,241 @@
+package publish
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"maplestory-world-llms-txt/internal/assemble"
+	"maplestory-world-llms-txt/internal/convert"
+	"maplestory-world-llms-txt/internal/document"
+)
+
+func sampleParts() []assemble.Part {
+	fetched := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	doc := func(path, title string, kind document.Kind, crumb []string, md string) assemble.Part {
+		u := "https://maplestoryworlds-creators.nexon.com/" + path
+		return assemble.Part{
+			Doc: document.Document{
+				ID: document.IDOf(u), URL: u, Lang: "en",
+				Kind: kind, Title: title, Breadcrumb: crumb,
+				Fetch: document.Fetch{FetchedAt: fetched},
+			},
+			Markdown: md,
+		}
+	}
+	return []assemble.Part{
+		doc("en/docs?postId=1", "Workspace", document.KindReference, []string{"Maker", "Basic Guide"},
+			"# Workspace\n\nSee [Hierarchy](/en/docs?postId=2) & [site](https://example.com/x).\n\n## Panels\n\n- one\n- two"),
+		doc("en/docs?postId=2", "Hierarchy", document.KindReference, []string{"Maker", "Basic Guide"},
+			"# Hierarchy\n\n![tree](https://cdn.example.com/tree.png)\n\nBack to [Workspace](/docs?postId=1#panels)."),
+		doc("en/apiReference/Components/SpriteRendererComponent", "SpriteRendererComponent", document.KindAPI, nil,
+			"# SpriteRendererComponent\n\nSee [Workspace](https://mod-developers.nexon.com/docs?postId=1).\n\n## Properties\n\n| Name | Type |\n| --- | --- |\n| Color | `Color` |"),
+	}
+}
+
+func readEPUB(t *testing.T, data []byte) (*zip.Reader, map[string]string) {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("reading the EPUB: %v", err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(b)
+	}
+	return zr, files
+}
+
+func wellFormed(t *testing.T, name, data string) {
+	t.Helper()
+	d := xml.NewDecoder(strings.NewReader(data))
+	for {
+		_, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			t.Errorf("%s is not well-formed XML: %v\n%s", name, err, data)
+			return
+		}
+	}
+}
+
+func TestWriteEPUB_Layout(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewBook("en", sampleParts()).WriteEPUB(&buf); err != nil {
+		t.Fatal(err)
+	}
+	zr, files := readEPUB(t, buf.Bytes())
+
+	first := zr.File[0]
+	if first.Name != "mimetype" || first.Method != zip.Store || files["mimetype"] != "application/epub+zip" {
+		t.Fatalf("the first entry must be the stored mimetype, got %s (method %d)", first.Name, first.Method)
+	}
+	for _, name := range []string{"META-INF/container.xml", "EPUB/package.opf", "EPUB/nav.xhtml",
+		"EPUB/doc0000.xhtml", "EPUB/doc0001.xhtml", "EPUB/doc0002.xhtml"} {
+		data, ok := files[name]
+		if !ok {
+			t.Fatalf("missing %s", name)
+		}
+		wellFormed(t, name, data)
+	}
+	if !first.Modified.Equal(time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)) {
+		t.Errorf("entries must carry the latest fetch time, got %v", first.Modified)
+	}
+
+	opf := files["EPUB/package.opf"]
+	for _, want := range []string{
+		`<meta property="dcterms:modified">2025-03-01T12:30:00Z</meta>`,
+		`<itemref idref="doc0000"/>` + "\n" + `<itemref idref="doc0001"/>` + "\n" + `<itemref idref="doc0002"/>`,
+		`<dc:identifier id="uid">urn:uuid:`,
+	} {
+		if !strings.Contains(opf, want) {
+			t.Errorf("expected %q in the package document:\n%s", want, opf)
+		}
+	}
+
+	nav := files["EPUB/nav.xhtml"]
+	want := "<ol>\n<li><span>Guides</span>\n<ol>\n<li><span>Maker</span>\n<ol>\n<li><span>Basic Guide</span>\n<ol>\n" +
+		"<li><a href=\"doc0000.xhtml\">Workspace</a></li>\n<li><a href=\"doc0001.xhtml\">Hierarchy</a></li>\n" +
+		"</ol>\n</li>\n</ol>\n</li>\n</ol>\n</li>\n" +
+		"<li><span>API Reference</span>\n<ol>\n<li><a href=\"doc0002.xhtml\">SpriteRendererComponent</a></li>\n</ol>\n</li>\n</ol>\n"
+	if !strings.Contains(nav, want) {
+		t.Errorf("unexpected navigation:\n%s\nwant:\n%s", nav, want)
+	}
+}
+
+func TestWriteEPUB_Chapters(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewBook("en", sampleParts()).WriteEPUB(&buf); err != nil {
+		t.Fatal(err)
+	}
+	_, files := readEPUB(t, buf.Bytes())
+
+	ws := files["EPUB/doc0000.xhtml"]
+	for _, want := range []string{
+		"<section id=\"s-workspace\">\n<h1>Workspace</h1>",
+		`<a href="doc0001.xhtml">Hierarchy</a> &amp; <a href="https://example.com/x">site</a>`,
+		"<section id=\"s-workspace--panels\">\n<h2>Panels</h2>",
+	} {
+		if !strings.Contains(ws, want) {
+			t.Errorf("expected %q in the first chapter:\n%s", want, ws)
+		}
+	}
+	h := files["EPUB/doc0001.xhtml"]
+	if !strings.Contains(h, `<a class="image" href="https://cdn.example.com/tree.png">[tree]</a>`) {
+		t.Errorf("remote images must become links:\n%s", h)
+	}
+	if !strings.Contains(h, `<a href="doc0000.xhtml">Workspace</a>`) {
+		t.Errorf("links without the language must resolve:\n%s", h)
+	}
+	if api := files["EPUB/doc0002.xhtml"]; !strings.Contains(api, `<a href="doc0000.xhtml">Workspace</a>`) {
+		t.Errorf("links to the former host must resolve:\n%s", api)
+	}
+}
+
+func TestWriteEPUB_Deterministic(t *testing.T) {
+	var a, b bytes.Buffer
+	if err := NewBook("en", sampleParts()).WriteEPUB(&a); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewBook("en", sampleParts()).WriteEPUB(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a.Bytes(), b.Bytes()) {
+		t.Fatal("the same corpus must yield the same EPUB")
+	}
+
+	var c bytes.Buffer
+	modified := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := NewBook("en", sampleParts(), WithTitle("Custom"), WithModified(modified)).WriteEPUB(&c); err != nil {
+		t.Fatal(err)
+	}
+	_, files := readEPUB(t, c.Bytes())
+	if opf := files["EPUB/package.opf"]; !strings.Contains(opf, "<dc:title>Custom</dc:title>") ||
+		!strings.Contains(opf, "2026-01-02T03:04:05Z") {
+		t.Errorf("options must apply:\n%s", opf)
+	}
+}
+
+var pngData = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// imageParts returns sampleParts with a captured screenshot whose placeholder
+// mdream escaped, and the images of the book.
+func imageParts(t *testing.T) ([]assemble.Part, Images) {
+	t.Helper()
+	parts := sampleParts()
+	parts[0].Doc.HTML = `<h2>Panels</h2><p><img src="/bbs/panels.png" alt="panels"></p>`
+	imgs, err := convert.Images(parts[0].Doc.HTML, parts[0].Doc.URL)
+	if err != nil || len(imgs) != 1 {
+		t.Fatalf("convert.Images = %v, %v", imgs, err)
+	}
+	placeholder := `\[` + strings.Trim(imgs[0].Placeholder, "[]") + `\]`
+	parts[0].Markdown += "\n\n" + placeholder
+	return parts, Images{
+		imgs[0].Src:                        {MediaType: "image/png", Data: pngData},
+		"https://cdn.example.com/tree.png": {MediaType: "image/png", Data: pngData},
+	}
+}
+
+func TestWriteEPUB_EmbedsImages(t *testing.T) {
+	parts, images := imageParts(t)
+	var buf bytes.Buffer
+	if err := NewBook("en", parts, WithImages(images)).WriteEPUB(&buf); err != nil {
+		t.Fatal(err)
+	}
+	_, files := readEPUB(t, buf.Bytes())
+
+	opf := files["EPUB/package.opf"]
+	var names []string
+	for name, data := range files {
+		if strings.HasPrefix(name, "EPUB/images/") {
+			if data != string(pngData) {
+				t.Errorf("%s holds %q", name, data)
+			}
+			names = append(names, strings.TrimPrefix(name, "EPUB/"))
+		}
+	}
+	if len(names) != 2 {
+		t.Fatalf("expected 2 images in the EPUB, got %v", names)
+	}
+	for _, name := range names {
+		if !strings.Contains(opf, `href="`+name+`" media-type="image/png"/>`) {
+			t.Errorf("%s missing from the manifest:\n%s", name, opf)
+		}
+	}
+	ws := files["EPUB/doc0000.xhtml"]
+	if !strings.Contains(ws, `<img src="images/`) || strings.Contains(ws, "[Image") {
+		t.Errorf("the placeholder must become the image:\n%s", ws)
+	}
+	if h := files["EPUB/doc0001.xhtml"]; !strings.Contains(h, `<img src="images/`) || !strings.Contains(h, `alt="tree"/>`) {
+		t.Errorf("Markdown images must be embedded:\n%s", h)
+	}
+	wellFormed(t, "EPUB/doc0000.xhtml", ws)
+}
+
+func TestXMLID(t *testing.T) {
+	for anchor, want := range map[string]string{
+		"workspace":             "s-workspace",
+		"2024-update/new-tools": "s-2024-update--new-tools",
+		"워크스페이스/패널":             "s-워크스페이스--패널",
+	} {
+		if got := xmlID(anchor); got != want {
+			t.Errorf("xmlID(%q) = %q, want %q", anchor, got, want)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package publish
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/markdown"
+)
+
+// xhtml renders the Markdown of converted documents as XHTML, which is valid
+// both in EPUB content documents and in HTML. It understands what mdream
+// emits: paragraphs, lists, block quotes, rules, fenced code, pipe tables and
+// inline links, images, emphasis, code spans and <br>.
+type xhtml struct {
+	// link rewrites a link target, e.g. to point into the publication.
+	link func(href string) string
+	// images writes <img> elements; otherwise an image becomes a link to its
+	// source, as EPUB may not reference remote images.
+	images bool
+	// embed returns the reference of an embedded image, e.g. a data URI, or
+	// false to fall back to the above.
+	embed func(src string) (string, bool)
+}
+
+var (
+	listItemRe = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	ruleRe     = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	entityRe   = regexp.MustCompile(`^&(#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+	brRe       = regexp.MustCompile(`^<br\s*/?>`)
+)
+
+// blocks writes the block-level XHTML of md. Section bodies hold no
+// headings; any found, e.g. in a block quote, keep their level.
+func (x *xhtml) blocks(b *strings.Builder, md string) {
+	for _, blk := range markdown.Parse(md) {
+		switch blk.Kind {
+		case markdown.Heading:
+			n := min(max(blk.Level, 1), 6)
+			fmt.Fprintf(b, "<h%d>%s</h%d>\n", n, x.inline(blk.Title), n)
+		case markdown.Code:
+			x.code(b, blk)
+		case markdown.Table:
+			x.table(b, blk.Lines)
+		default:
+			x.text(b, blk.Lines)
+		}
+	}
+}
+
+func (x *xhtml) code(b *strings.Builder, blk markdown.Block) {
+	lines := blk.Lines[1:]
+	if blk.Closed && len(lines) > 0 {
+		lines = lines[:len(lines)-1]
+	}
+	b.WriteString("<pre><code")
+	if lang := markdown.FenceInfo(blk.Lines[0]); lang != "" {
+		fmt.Fprintf(b, ` class="language-%s"`, html.EscapeString(lang))
+	}
+	b.WriteString(">" + html.EscapeString(strings.Join(lines, "\n")) + "</code></pre>\n")
+}
+
+func (x *xhtml) table(b *strings.Builder, rows []string) {
+	b.WriteString("<table>\n")
+	if len(rows) >= 2 && isDelimiterRow(rows[1]) {
+		b.WriteString("<thead><tr>")
+		for _, c := range splitRow(rows[0]) {
+			b.WriteString("<th>" + x.inline(c) + "</th>")
+		}
+		b.WriteString("</tr></thead>\n")
+		rows = rows[2:]
+	}
+	b.WriteString("<tbody>\n")
+	for _, r := range rows {
+		b.WriteString("<tr>")
+		for _, c := range splitRow(r) {
+			b.WriteString("<td>" + x.inline(c) + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+}
+
+// text writes a run of non-blank lines: block quotes, rules, lists and the
+// paragraphs between them.
+func (x *xhtml) text(b *strings.Builder, lines []string) {
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			b.WriteString("<p>" + x.inline(strings.Join(para, "\n")) + "</p>\n")
+			para = nil
+		}
+	}
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(line), ">"):
+			flush()
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(q, " "))
+			}
+			b.WriteString("<blockquote>\n")
+			x.blocks(b, strings.Join(quoted, "\n"))
+			b.WriteString("</blockquote>\n")
+		case ruleRe.MatchString(line):
+			flush()
+			b.WriteString("<hr/>\n")
+			i++
+		case listItemRe.MatchString(line):
+			flush()
+			i += x.list(b, lines[i:])
+		default:
+			para = append(para, strings.TrimSpace(line))
+			i++
+		}
+	}
+	flush()
+}
+
+// listItem is an item of a (possibly nested) list.
+type listItem struct {
+	text     []string
+	children []string
+}
+
+// list writes the list starting at lines[0] and returns the number of lines
+// it spans. Items indented deeper than the first one, and the lines that
+// follow them, form nested lists; other unmarked lines continue the item.
+func (x *xhtml) list(b *strings.Builder, lines []string) int {
+	first := listItemRe.FindStringSubmatch(lines[0])
+	indent, ordered := len(first[1]), isOrdered(first[2])
+	var (
+		items []*listItem
+		n     int
+	)
+loop:
+	for ; n < len(lines); n++ {
+		m := listItemRe.FindStringSubmatch(lines[n])
+		switch {
+		case ruleRe.MatchString(lines[n]) || strings.HasPrefix(strings.TrimSpace(lines[n]), ">"):
+			break loop
+		case m != nil && len(m[1]) < indent:
+			break loop
+		case m != nil && len(m[1]) == indent:
+			// A sibling of the other kind starts a new list
+			if isOrdered(m[2]) != ordered {
+				break loop
+			}
+			items = append(items, &listItem{text: []string{m[3]}})
+		default:
+			last := items[len(items)-1]
+			if m != nil || len(last.children) > 0 {
+				last.children = append(last.children, lines[n])
+			} else {
+				last.text = append(last.text, strings.TrimSpace(lines[n]))
+			}
+		}
+	}
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	b.WriteString("<" + tag)
+	if start := strings.TrimRight(first[2], ".)"); ordered && start != "1" {
+		fmt.Fprintf(b, ` start="%s"`, strings.TrimLeft(start, "0"))
+	}
+	b.WriteString(">\n")
+	for _, it := range items {
+		b.WriteString("<li>" + x.inline(strings.Join(it.text, "\n")))
+		if len(it.children) > 0 {
+			b.WriteString("\n")
+			x.text(b, it.children)
+		}
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return n
+}
+
+// inline returns the XHTML of a run of inline Markdown.
+func (x *xhtml) inline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_{}[]()#+-.!|<>\"'~", s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '`':
+			if code, n := codeSpan(s[i:]); n > 0 {
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i += n
+				continue
+			}
+		case c == '!' && strings.HasPrefix(s[i+1:], "["):
+			if text, dest, n := linkAt(s[i+1:]); n > 0 {
+				b.WriteString(x.image(text, dest))
+				i += 1 + n
+				continue
+			}
+		case c == '[':
+			if text, dest, n := linkAt(s[i:]); n > 0 {
+				fmt.Fprintf(&b, `<a href="%s">%s</a>`, html.EscapeString(x.href(dest)), x.inline(text))
+				i += n
+				continue
+			}
+		case c == '*' || c == '_':
+			if tag, inner, n := emphasisAt(s, i); n > 0 {
+				b.WriteString("<" + tag + ">" + x.inline(inner) + "</" + tag + ">")
+				i += n
+				continue
+			}
+			// A run that opens nothing is literal text
+			j := i
+			for j < len(s) && s[j] == c {
+				j++
+			}
+			b.WriteString(s[i:j])
+			i = j
+			continue
+		case c == '<':
+			if m := brRe.FindString(s[i:]); m != "" {
+				b.WriteString("<br/>")
+				i += len(m)
+				continue
+			}
+		case c == '&':
+			// Entities are decoded and re-escaped: XHTML knows only the XML ones
+			if m := entityRe.FindString(s[i:]); m != "" {
+				b.WriteString(html.EscapeString(html.UnescapeString(m)))
+				i += len(m)
+				continue
+			}
+		}
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+func (x *xhtml) href(dest string) string {
+	dest = html.UnescapeString(dest)
+	if x.link != nil {
+		return x.link(dest)
+	}
+	return dest
+}
+
+func (x *xhtml) image(alt, src string) string {
+	alt = html.UnescapeString(alt)
+	if x.embed != nil {
+		if ref, ok := x.embed(html.UnescapeString(src)); ok {
+			return fmt.Sprintf(`<img src="%s" alt="%s"/>`, html.EscapeString(ref), html.EscapeString(alt))
+		}
+	}
+	if x.images {
+		return fmt.Sprintf(`<img src="%s" alt="%s" loading="lazy"/>`, html.EscapeString(html.UnescapeString(src)), html.EscapeString(alt))
+	}
+	if alt == "" {
+		alt = "image"
+	}
+	return fmt.Sprintf(`<a class="image" href="%s">[%s]</a>`, html.EscapeString(html.UnescapeString(src)), html.EscapeString(alt))
+}
+
+// codeSpan parses a code span at the start of s and returns its content and
+// length, or 0 when the backticks are not closed.
+func codeSpan(s string) (string, int) {
+	n := 0
+	for n < len(s) && s[n] == '`' {
+		n++
+	}
+	fence := s[:n]
+	for j := n; j < len(s); {
+		k := strings.Index(s[j:], fence)
+		if k < 0 {
+			return "", 0
+		}
+		end := j + k
+		if end+n < len(s) && s[end+n] == '`' {
+			// A longer run does not close the span
+			for end < len(s) && s[end] == '`' {
+				end++
+			}
+			j = end
+			continue
+		}
+		code := s[n:end]
+		if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+			code = code[1 : len(code)-1]
+		}
+		return code, end + n
+	}
+	return "", 0
+}
+
+// linkAt parses "[text](dest)" or "[text](dest "title")" at the start of s
+// and returns the text, the destination and the length, or 0 when s does not
+// start with a link.
+func linkAt(s string) (text, dest string, n int) {
+	depth := 0
+	closeText := -1
+	for i := 0; i < len(s) && closeText < 0; i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				closeText = i
+			}
+		}
+	}
+	if closeText < 0 || closeText+1 >= len(s) || s[closeText+1] != '(' {
+		return "", "", 0
+	}
+	depth = 0
+	for i := closeText + 1; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				dest = strings.TrimSpace(s[closeText+2 : i])
+				if j := strings.Index(dest, ` "`); j >= 0 && strings.HasSuffix(dest, `"`) {
+					dest = strings.TrimSpace(dest[:j])
+				}
+				dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+				return s[1:closeText], dest, i + 1
+			}
+		}
+	}
+	return "", "", 0
+}
+
+// emphasisAt parses emphasis opened by the run of * or _ at s[i]: a run of
+// two or more is strong, one is em. Underscores only count at word
+// boundaries, so snake_case names stay intact.
+func emphasisAt(s string, i int) (tag, inner string, n int) {
+	c := s[i]
+	run := 0
+	for i+run < len(s) && s[i+run] == c {
+		run++
+	}
+	marker := string(c)
+	tag = "em"
+	if run >= 2 {
+		marker, tag = strings.Repeat(string(c), 2), "strong"
+	}
+	start := i + len(marker)
+	if start >= len(s) || s[start] == ' ' || c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return "", "", 0
+	}
+	for j := start + 1; j < len(s); j++ {
+		if !strings.HasPrefix(s[j:], marker) || s[j-1] == ' ' || s[j-1] == '\\' {
+			continue
+		}
+		end := j + len(marker)
+		if c == '_' && end < len(s) && isWordByte(s[end]) {
+			continue
+		}
+		return tag, s[start:j], end - i
+	}
+	return "", "", 0
+}
+
+func isOrdered(marker string) bool { return !strings.ContainsAny(marker, "-*+") }
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// splitRow returns the cells of a pipe table row; "\|" is a literal pipe.
+func splitRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+	var (
+		cells []string
+		cur   strings.Builder
+	)
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cur.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteByte(row[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cur.String()))
+}
+
+func isDelimiterRow(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "|") && strings.Trim(s, "|-: ") == "" && strings.Contains(s, "-")
+}
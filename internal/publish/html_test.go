@@ -0,0 +1,62 @@
+package publish
+
+import (
+	"strings"
+	"testing"
+)
+
+func render(x *xhtml, md string) string {
+	var b strings.Builder
+	x.blocks(&b, md)
+	return b.String()
+}
+
+func TestXHTML_Inline(t *testing.T) {
+	x := &xhtml{}
+	cases := map[string]string{
+		"**Workspace** is *here*":             "<strong>Workspace</strong> is <em>here</em>",
+		"call `a < b` now":                    "call <code>a &lt; b</code> now",
+		"[Model](/docs?postId=55)":            `<a href="/docs?postId=55">Model</a>`,
+		"snake_case_name and _em_":            "snake_case_name and <em>em</em>",
+		`a \| b \*not em\*`:                   "a | b *not em*",
+		"line<br>break &amp; &nbsp;x":         "line<br/>break &amp;  x",
+		"![alt](https://x/i.png?a=1&amp;b=2)": `<a class="image" href="https://x/i.png?a=1&amp;b=2">[alt]</a>`,
+		"[![b](i.png)](https://x)":            `<a href="https://x"><a class="image" href="i.png">[b]</a></a>`,
+		"2 * 3 and [unclosed":                 "2 * 3 and [unclosed",
+	}
+	for in, want := range cases {
+		if got := x.inline(in); got != want {
+			t.Errorf("inline(%q) = %q, want %q", in, got, want)
+		}
+	}
+	img := &xhtml{images: true}
+	if got := img.inline("![a \"q\"](u.png)"); got != `<img src="u.png" alt="a &#34;q&#34;" loading="lazy"/>` {
+		t.Errorf("unexpected image: %q", got)
+	}
+	linked := &xhtml{link: func(h string) string { return "#" + h }}
+	if got := linked.inline("[t](x&amp;y)"); got != `<a href="#x&amp;y">t</a>` {
+		t.Errorf("links must go through the rewriter: %q", got)
+	}
+}
+
+func TestXHTML_Blocks(t *testing.T) {
+	md := "Intro line\nsecond line\n\n- one\n- two\n  - nested\n  more\n- three\n\n3. c\n4. d\n\n> **Tip.** Quote\n\n---\n\n" +
+		"| A | B |\n| --- | --- |\n| 1 \\| 2 | x<br>y |\n\n```lua\nif a < b then\n\nend\n```"
+	want := "<p>Intro line\nsecond line</p>\n" +
+		"<ul>\n<li>one</li>\n<li>two\n<ul>\n<li>nested\nmore</li>\n</ul>\n</li>\n<li>three</li>\n</ul>\n" +
+		"<ol start=\"3\">\n<li>c</li>\n<li>d</li>\n</ol>\n" +
+		"<blockquote>\n<p><strong>Tip.</strong> Quote</p>\n</blockquote>\n" +
+		"<hr/>\n" +
+		"<table>\n<thead><tr><th>A</th><th>B</th></tr></thead>\n<tbody>\n<tr><td>1 | 2</td><td>x<br/>y</td></tr>\n</tbody>\n</table>\n" +
+		"<pre><code class=\"language-lua\">if a &lt; b then\n\nend</code></pre>\n"
+	if got := render(&xhtml{}, md); got != want {
+		t.Fatalf("unexpected XHTML:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestXHTML_TableWithoutHeader(t *testing.T) {
+	got := render(&xhtml{}, "| Import Image | Imports image file. |\n| Import Sound | Imports sound. |")
+	if strings.Contains(got, "<thead>") || strings.Count(got, "<tr>") != 2 {
+		t.Fatalf("rows without a delimiter row are body rows:\n%s", got)
+	}
+}
@@ -0,0 +1,257 @@
+// Package search implements a small on-disk BM25 index over the crawled
+// documentation so that questions like "which component has property X?" can
+// be answered locally without an external search service.
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// indexVersion is bumped whenever the persisted format changes.
+const indexVersion = 1
+
+// BM25 parameters.
+const (
+	k1 = 1.2
+	b  = 0.75
+	// titleBoost multiplies the term frequency of title terms.
+	titleBoost = 3
+)
+
+// Entry is an indexed unit: a whole document or one of its chunks.
+type Entry struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	Text  string `json:"text"`
+}
+
+// posting records how often a term occurs in an entry.
+type posting struct {
+	Doc int `json:"d"`
+	TF  int `json:"f"`
+}
+
+// Index is an inverted index ranked with BM25.
+type Index struct {
+	Version  int                  `json:"version"`
+	Entries  []Entry              `json:"entries"`
+	Lengths  []int                `json:"lengths"`
+	Postings map[string][]posting `json:"postings"`
+	total    int
+}
+
+// Result is a ranked search hit.
+type Result struct {
+	ID      string  `json:"id"`
+	Title   string  `json:"title"`
+	URL     string  `json:"url"`
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+// NewIndex returns an empty index.
+func NewIndex() *Index {
+	return &Index{Version: indexVersion, Postings: make(map[string][]posting)}
+}
+
+// Add indexes e. Title terms weigh more than body terms.
+func (ix *Index) Add(e Entry) {
+	doc := len(ix.Entries)
+	tf := make(map[string]int)
+	for _, t := range Tokenize(e.Title) {
+		tf[t] += titleBoost
+	}
+	for _, t := range Tokenize(e.Text) {
+		tf[t]++
+	}
+	length := 0
+	for t, n := range tf {
+		ix.Postings[t] = append(ix.Postings[t], posting{Doc: doc, TF: n})
+		length += n
+	}
+	ix.Entries = append(ix.Entries, e)
+	ix.Lengths = append(ix.Lengths, length)
+	ix.total += length
+}
+
+// Len returns the number of indexed entries.
+func (ix *Index) Len() int { return len(ix.Entries) }
+
+// Search ranks entries against query and returns at most limit results
+// (all matches when limit <= 0).
+func (ix *Index) Search(query string, limit int) []Result {
+	terms := uniq(Tokenize(query))
+	if len(terms) == 0 || len(ix.Entries) == 0 {
+		return nil
+	}
+	n := float64(len(ix.Entries))
+	avg := float64(ix.total) / n
+	scores := make(map[int]float64)
+	for _, t := range terms {
+		ps := ix.Postings[t]
+		if len(ps) == 0 {
+			continue
+		}
+		idf := math.Log(1 + (n-float64(len(ps))+0.5)/(float64(len(ps))+0.5))
+		for _, p := range ps {
+			tf := float64(p.TF)
+			norm := 1 - b + b*float64(ix.Lengths[p.Doc])/avg
+			scores[p.Doc] += idf * tf * (k1 + 1) / (tf + k1*norm)
+		}
+	}
+
+	docs := make([]int, 0, len(scores))
+	for d := range scores {
+		docs = append(docs, d)
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		if scores[docs[i]] != scores[docs[j]] {
+			return scores[docs[i]] > scores[docs[j]]
+		}
+		return docs[i] < docs[j]
+	})
+	if limit > 0 && len(docs) > limit {
+		docs = docs[:limit]
+	}
+
+	results := make([]Result, 0, len(docs))
+	for _, d := range docs {
+		e := ix.Entries[d]
+		results = append(results, Result{
+			ID:      e.ID,
+			Title:   e.Title,
+			URL:     e.URL,
+			Score:   scores[d],
+			Snippet: Snippet(e.Text, terms, 160),
+		})
+	}
+	return results
+}
+
+// Snippet returns the line of text that matches the most query terms, cut to
+// about width runes around the first match.
+func Snippet(text string, terms []string, width int) string {
+	want := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		want[t] = true
+	}
+	best, bestHits := "", 0
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		hits := 0
+		for _, t := range uniq(Tokenize(line)) {
+			if want[t] {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			best, bestHits = line, hits
+		}
+		if best == "" {
+			best = line
+		}
+	}
+	best = strings.Join(strings.Fields(best), " ")
+	if utf8.RuneCountInString(best) <= width {
+		return best
+	}
+
+	// Lowercasing rune by rune keeps offsets aligned with rs; strings.ToLower
+	// may change the byte length of non-ASCII text.
+	rs := []rune(best)
+	lower := make([]rune, len(rs))
+	for i, r := range rs {
+		lower[i] = unicode.ToLower(r)
+	}
+	start := 0
+	for _, t := range terms {
+		if i := runeIndex(lower, []rune(t)); i >= 0 {
+			start = i
+			break
+		}
+	}
+	from := start - width/4
+	if from < 0 {
+		from = 0
+	}
+	to := from + width
+	if to > len(rs) {
+		to, from = len(rs), max(0, len(rs)-width)
+	}
+	s := string(rs[from:to])
+	if from > 0 {
+		s = "…" + s
+	}
+	if to < len(rs) {
+		s += "…"
+	}
+	return s
+}
+
+// runeIndex returns the index of the first occurrence of sub in s, or -1.
+func runeIndex(s, sub []rune) int {
+	if len(sub) == 0 {
+		return -1
+	}
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if slices.Equal(s[i:i+len(sub)], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+func uniq(ss []string) []string {
+	seen := make(map[string]bool, len(ss))
+	out := ss[:0:0]
+	for _, s := range ss {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Save writes the index to path as JSON.
+func (ix *Index) Save(path string) error {
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Load reads an index written by Save.
+func Load(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ix := NewIndex()
+	if err := json.Unmarshal(data, ix); err != nil {
+		return nil, err
+	}
+	if ix.Version != indexVersion {
+		return nil, fmt.Errorf("unsupported index version %d (want %d); rebuild the index", ix.Version, indexVersion)
+	}
+	if len(ix.Lengths) != len(ix.Entries) {
+		return nil, fmt.Errorf("corrupt index: %d lengths for %d entries", len(ix.Lengths), len(ix.Entries))
+	}
+	for _, l := range ix.Lengths {
+		ix.total += l
+	}
+	return ix, nil
+}
@@ -0,0 +1,96 @@
+package search
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sampleIndex() *Index {
+	ix := NewIndex()
+	ix.Add(Entry{ID: "1", Title: "AIChaseComponent", URL: "https://example.com/AIChaseComponent",
+		Text: "AI that allows monsters to track players.\n| float DetectionRange |\n| Range of trace detection. |"})
+	ix.Add(Entry{ID: "2", Title: "TransformComponent", URL: "https://example.com/TransformComponent",
+		Text: "Manages the position, rotation and scale of an entity."})
+	ix.Add(Entry{ID: "3", Title: "워크스페이스", URL: "https://example.com/ko/472",
+		Text: "워크스페이스를 검색하는 방법을 소개합니다."})
+	return ix
+}
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("IsChaseNearPlayer, UIGroupComponent 워크스페이스를")
+	want := []string{
+		"ischasenearplayer", "is", "chase", "near", "player",
+		"uigroupcomponent", "ui", "group", "component",
+		"워크", "크스", "스페", "페이", "이스", "스를",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Tokenize:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestSearch_RanksPropertyMatch(t *testing.T) {
+	res := sampleIndex().Search("DetectionRange", 5)
+	if len(res) != 1 || res[0].ID != "1" {
+		t.Fatalf("expected AIChaseComponent, got %+v", res)
+	}
+	if !strings.Contains(res[0].Snippet, "DetectionRange") {
+		t.Fatalf("snippet should contain the match, got %q", res[0].Snippet)
+	}
+
+	res = sampleIndex().Search("detection range", 5)
+	if len(res) == 0 || res[0].ID != "1" {
+		t.Fatalf("camelCase parts should match, got %+v", res)
+	}
+}
+
+func TestSearch_KoreanParticles(t *testing.T) {
+	res := sampleIndex().Search("워크스페이스 검색", 5)
+	if len(res) != 1 || res[0].ID != "3" {
+		t.Fatalf("expected Korean document, got %+v", res)
+	}
+}
+
+func TestSearch_TitleBoostAndLimit(t *testing.T) {
+	res := sampleIndex().Search("component", 1)
+	if len(res) != 1 {
+		t.Fatalf("expected limit to apply, got %d results", len(res))
+	}
+	if res := sampleIndex().Search("nothing-matches-this", 0); len(res) != 0 {
+		t.Fatalf("expected no results, got %+v", res)
+	}
+}
+
+func TestSaveLoad_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+	ix := sampleIndex()
+	if err := ix.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	a, b := ix.Search("entity position", 3), got.Search("entity position", 3)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatalf("results differ after reload:\n%+v\n%+v", a, b)
+	}
+}
+
+func TestSnippet_NonASCIICaseFolding(t *testing.T) {
+	// İ lowercases to a longer byte sequence, which must not shift the cut
+	text := strings.Repeat("İ", 120) + " needle " + strings.Repeat("tail ", 50)
+	s := Snippet(text, []string{"needle"}, 40)
+	if !strings.Contains(s, "needle") {
+		t.Fatalf("snippet should contain the match, got %q", s)
+	}
+}
+
+func TestSnippet_TruncatesAroundMatch(t *testing.T) {
+	text := strings.Repeat("filler ", 50) + "needle " + strings.Repeat("tail ", 50)
+	s := Snippet(text, []string{"needle"}, 40)
+	if !strings.Contains(s, "needle") || !strings.HasPrefix(s, "…") || !strings.HasSuffix(s, "…") {
+		t.Fatalf("unexpected snippet %q", s)
+	}
+}
@@ -0,0 +1,102 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenize splits text into index terms. Latin words and numbers are
+// lowercased; camelCase and PascalCase identifiers such as "DetectionRange"
+// are indexed both whole and by part ("detectionrange", "detection",
+// "range"). Runs of Hangul are indexed as overlapping syllable bigrams, which
+// matches Korean words regardless of attached particles (e.g. "워크스페이스를").
+func Tokenize(s string) []string {
+	var (
+		tokens []string
+		run    []rune
+		hangul bool
+	)
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if hangul {
+			tokens = append(tokens, hangulBigrams(run)...)
+		} else {
+			tokens = append(tokens, wordTerms(string(run))...)
+		}
+		run = run[:0]
+	}
+	for _, r := range s {
+		switch {
+		case isHangul(r):
+			if !hangul {
+				flush()
+				hangul = true
+			}
+			run = append(run, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if hangul {
+				flush()
+				hangul = false
+			}
+			run = append(run, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return tokens
+}
+
+func isHangul(r rune) bool {
+	return unicode.Is(unicode.Hangul, r)
+}
+
+// hangulBigrams returns overlapping two-syllable terms; a single syllable is
+// returned as is.
+func hangulBigrams(run []rune) []string {
+	if len(run) == 1 {
+		return []string{string(run)}
+	}
+	out := make([]string, 0, len(run)-1)
+	for i := 0; i+1 < len(run); i++ {
+		out = append(out, string(run[i:i+2]))
+	}
+	return out
+}
+
+// wordTerms returns the lowercased word plus its camelCase parts, if any.
+func wordTerms(w string) []string {
+	lower := strings.ToLower(w)
+	parts := splitCamel(w)
+	if len(parts) < 2 {
+		return []string{lower}
+	}
+	out := []string{lower}
+	for _, p := range parts {
+		out = append(out, strings.ToLower(p))
+	}
+	return out
+}
+
+// splitCamel splits "IsChaseNearPlayer" into "Is", "Chase", "Near", "Player"
+// and "UIGroupComponent" into "UI", "Group", "Component".
+func splitCamel(w string) []string {
+	rs := []rune(w)
+	var (
+		parts []string
+		start int
+	)
+	for i := 1; i < len(rs); i++ {
+		prev, cur := rs[i-1], rs[i]
+		boundary := unicode.IsLower(prev) && unicode.IsUpper(cur) ||
+			unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(rs) && unicode.IsLower(rs[i+1]) ||
+			unicode.IsDigit(prev) != unicode.IsDigit(cur)
+		if boundary {
+			parts = append(parts, string(rs[start:i]))
+			start = i
+		}
+	}
+	return append(parts, string(rs[start:]))
+}
@@ -0,0 +1,243 @@
+// Package section splits converted documents into a tree of heading-delimited
+// sections, so output, links and chunks can address a single section of a
+// concatenated file, e.g. reference.md#workspace/searching-workspace.
+//
+// The pages use heading levels loosely: a document repeats its title as "#",
+// its sections are "#" too, and subsections jump to "####" or "#####". The
+// tree normalizes this: the document title is the root, every other heading
+// nests one level below the nearest preceding heading with a smaller source
+// level, and the root itself sits one level below its nav breadcrumb.
+package section
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/markdown"
+)
+
+// MaxLevel is the deepest heading level Markdown can express. Normalized
+// levels are clamped to it; Parent still records the true nesting.
+const MaxLevel = 6
+
+// Section is a heading and the content up to the next heading.
+type Section struct {
+	// Level is the normalized heading level.
+	Level int    `json:"level"`
+	Title string `json:"title"`
+	// Slug is the section's anchor, unique within its document.
+	Slug string `json:"slug"`
+	// Anchor addresses the section within a concatenated file:
+	// "<document slug>/<section slug>", or the document slug for the root.
+	Anchor string `json:"anchor"`
+	// Body is the section's Markdown without its heading and without the
+	// subsections that follow it.
+	Body string `json:"body"`
+	// Parent is the index of the enclosing section, -1 for the root.
+	Parent int `json:"parent"`
+}
+
+// Tree is the section tree of one document. Sections are in document order;
+// Sections[0] is the root, titled after the document and holding the content
+// that precedes the first section heading.
+type Tree struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	Breadcrumb []string  `json:"breadcrumb,omitempty"`
+	Sections   []Section `json:"sections"`
+}
+
+// Root returns the document's root section.
+func (t *Tree) Root() Section { return t.Sections[0] }
+
+// Path returns the titles leading to section i: the nav breadcrumb, the
+// document title and the enclosing sections, ending with section i itself.
+func (t *Tree) Path(i int) []string {
+	var titles []string
+	for ; i >= 0; i = t.Sections[i].Parent {
+		titles = append(titles, t.Sections[i].Title)
+	}
+	path := append([]string{}, t.Breadcrumb...)
+	for j := len(titles) - 1; j >= 0; j-- {
+		path = append(path, titles[j])
+	}
+	return path
+}
+
+// Children returns the indexes of the sections directly below section i.
+func (t *Tree) Children(i int) []int {
+	var out []int
+	for j, s := range t.Sections {
+		if s.Parent == i {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
+// Find returns the index of the section with the given slug, or -1.
+func (t *Tree) Find(slug string) int {
+	for i, s := range t.Sections {
+		if s.Slug == slug {
+			return i
+		}
+	}
+	return -1
+}
+
+// open is a section that later headings may nest under: its index, its
+// heading level in the source and its depth below the root.
+type open struct{ index, level, depth int }
+
+// Parse builds the section tree of a document. The document slug is derived
+// from its title; pass docSlug to override it, e.g. with a slug deduplicated
+// across a concatenated file.
+func Parse(d document.Document, docSlug string) *Tree {
+	if docSlug == "" {
+		docSlug = Slugify(d.Title)
+	}
+	t := &Tree{ID: d.ID, URL: d.URL, Breadcrumb: d.Breadcrumb}
+	rootLevel := min(len(d.Breadcrumb)+1, MaxLevel)
+	t.Sections = append(t.Sections, Section{Level: rootLevel, Title: d.Title, Slug: docSlug, Anchor: docSlug, Parent: -1})
+
+	var (
+		slugs  = NewSlugger()
+		bodies = [][]string{nil}
+		// The root counts as source level 0 so that every heading nests below it.
+		stack  = []open{{0, 0, 0}}
+		titled = false
+	)
+	for _, b := range markdown.Parse(d.Markdown) {
+		if b.Kind != markdown.Heading {
+			n := len(t.Sections) - 1
+			bodies[n] = append(bodies[n], b.Text())
+			continue
+		}
+		// The page repeats the document title as its first heading.
+		if !titled && len(t.Sections) == 1 && len(bodies[0]) == 0 && sameTitle(b.Title, d.Title) {
+			titled = true
+			continue
+		}
+		for len(stack) > 1 && stack[len(stack)-1].level >= b.Level {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		title := markdown.PlainText(b.Title)
+		slug := slugs.Slug(title)
+		t.Sections = append(t.Sections, Section{
+			Level:  min(rootLevel+parent.depth+1, MaxLevel),
+			Title:  title,
+			Slug:   slug,
+			Anchor: docSlug + "/" + slug,
+			Parent: parent.index,
+		})
+		bodies = append(bodies, nil)
+		stack = append(stack, open{len(t.Sections) - 1, b.Level, parent.depth + 1})
+	}
+	for i := range t.Sections {
+		t.Sections[i].Body = strings.Join(bodies[i], "\n\n")
+	}
+	return t
+}
+
+// ParseAll builds the section trees of documents concatenated into one file,
+// giving repeated titles unique document slugs (scene, scene-1, ...) so
+// every anchor in the file is unique.
+func ParseAll(docs []document.Document) []*Tree {
+	slugs := NewSlugger()
+	trees := make([]*Tree, len(docs))
+	for i, d := range docs {
+		trees[i] = Parse(d, slugs.Slug(d.Title))
+	}
+	return trees
+}
+
+// Markdown writes the tree back as Markdown with the normalized levels: the
+// root heading, its body, then every section in document order.
+func (t *Tree) Markdown() string {
+	var parts []string
+	for _, s := range t.Sections {
+		parts = append(parts, strings.Repeat("#", s.Level)+" "+s.Title)
+		if s.Body != "" {
+			parts = append(parts, s.Body)
+		}
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+func sameTitle(heading, title string) bool {
+	return strings.EqualFold(markdown.PlainText(heading), markdown.PlainText(title))
+}
+
+// Slugify turns a heading into an anchor the way GitHub does: lowercase,
+// letters (Hangul included) and digits kept, spaces and hyphens turned into
+// hyphens, everything else dropped. "Scene's Functions" -> "scenes-functions".
+func Slugify(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(markdown.PlainText(s)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			b.WriteRune(r)
+		case r == ' ' || r == '-':
+			b.WriteByte('-')
+		}
+	}
+	slug := b.String()
+	for strings.Contains(slug, "--") {
+		slug = strings.ReplaceAll(slug, "--", "-")
+	}
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		return "section"
+	}
+	return slug
+}
+
+// Slugger hands out unique slugs, appending -1, -2, ... to repeated ones.
+type Slugger struct {
+	seen map[string]bool
+}
+
+// NewSlugger constructs an empty Slugger.
+func NewSlugger() *Slugger { return &Slugger{seen: make(map[string]bool)} }
+
+// Slug returns the slug of s, made unique among the slugs returned so far.
+func (sl *Slugger) Slug(s string) string {
+	base := Slugify(s)
+	slug := base
+	for n := 1; sl.seen[slug]; n++ {
+		slug = base + "-" + strconv.Itoa(n)
+	}
+	sl.seen[slug] = true
+	return slug
+}
+
+// Record is a section flattened for JSONL output.
+type Record struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Path is the nav breadcrumb of the document followed by the titles of
+	// the document and of the sections enclosing this one, as Tree.Path.
+	Path []string `json:"path"`
+	Section
+}
+
+// WriteJSONL writes every section of the trees to w, one JSON object per
+// line. Record IDs are the document ID followed by "#" and the anchor.
+func WriteJSONL(w io.Writer, trees []*Tree) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, t := range trees {
+		for i, s := range t.Sections {
+			r := Record{ID: t.ID + "#" + s.Anchor, URL: t.URL, Path: t.Path(i), Section: s}
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package section
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/document"
+)
+
+const workspaceMD = `# Workspace
+
+![custom](https://img.shields.io/badge.png)
+
+# Course Introduction
+
+You can use **Workspace** to manage entities.
+
+##### Reference Guide
+
+[Model](/docs?postId=55)
+
+# Searching Workspace
+
+Search from the search box.
+
+` + "```lua\n# not a heading\n```" + `
+
+# MyDesk
+
+#### Adding Resource to MyDesk
+
+Drag files in.
+
+#### Deleting MyDesk Folder
+
+Right-click the folder.
+`
+
+func workspaceDoc() document.Document {
+	return document.Document{
+		ID:         "en/docs/472",
+		Title:      "Workspace",
+		URL:        "https://example.com/docs?postId=472",
+		Breadcrumb: []string{"Maker", "Basic Guide"},
+		Markdown:   workspaceMD,
+	}
+}
+
+func TestParse_BuildsNormalizedTree(t *testing.T) {
+	tree := Parse(workspaceDoc(), "")
+
+	type row struct {
+		level  int
+		title  string
+		anchor string
+		parent int
+	}
+	want := []row{
+		{3, "Workspace", "workspace", -1},
+		{4, "Course Introduction", "workspace/course-introduction", 0},
+		{5, "Reference Guide", "workspace/reference-guide", 1},
+		{4, "Searching Workspace", "workspace/searching-workspace", 0},
+		{4, "MyDesk", "workspace/mydesk", 0},
+		{5, "Adding Resource to MyDesk", "workspace/adding-resource-to-mydesk", 4},
+		{5, "Deleting MyDesk Folder", "workspace/deleting-mydesk-folder", 4},
+	}
+	if len(tree.Sections) != len(want) {
+		t.Fatalf("expected %d sections, got %d: %+v", len(want), len(tree.Sections), tree.Sections)
+	}
+	for i, w := range want {
+		s := tree.Sections[i]
+		if s.Level != w.level || s.Title != w.title || s.Anchor != w.anchor || s.Parent != w.parent {
+			t.Errorf("section %d = {%d %q %q %d}, want %+v", i, s.Level, s.Title, s.Anchor, s.Parent, w)
+		}
+	}
+
+	if got := tree.Root().Body; !strings.HasPrefix(got, "![custom]") {
+		t.Errorf("expected the badge line in the root body, got %q", got)
+	}
+	search := tree.Sections[tree.Find("searching-workspace")]
+	if !strings.Contains(search.Body, "# not a heading") || strings.Contains(search.Body, "MyDesk") {
+		t.Errorf("unexpected body of Searching Workspace: %q", search.Body)
+	}
+	if got := strings.Join(tree.Path(5), " > "); got != "Maker > Basic Guide > Workspace > MyDesk > Adding Resource to MyDesk" {
+		t.Errorf("unexpected path %q", got)
+	}
+	if got := tree.Children(4); len(got) != 2 || got[0] != 5 || got[1] != 6 {
+		t.Errorf("unexpected children of MyDesk: %v", got)
+	}
+}
+
+func TestParse_KeepsUntitledDocumentAndClampsLevels(t *testing.T) {
+	d := document.Document{
+		Title:      "Deep",
+		Breadcrumb: []string{"a", "b", "c", "d"},
+		Markdown:   "Intro.\n\n# One\n\n## Two\n\n### Three",
+	}
+	tree := Parse(d, "deep-1")
+	if len(tree.Sections) != 4 || tree.Root().Body != "Intro." {
+		t.Fatalf("expected the root to keep the intro and 3 sections, got %+v", tree.Sections)
+	}
+	for i, want := range []int{5, 6, 6, 6} {
+		if got := tree.Sections[i].Level; got != want {
+			t.Errorf("section %d level = %d, want %d", i, got, want)
+		}
+	}
+	if tree.Sections[3].Parent != 2 || tree.Sections[3].Anchor != "deep-1/three" {
+		t.Errorf("clamping must keep the nesting and the doc slug: %+v", tree.Sections[3])
+	}
+}
+
+func TestTree_MarkdownRewritesLevels(t *testing.T) {
+	got := Parse(workspaceDoc(), "").Markdown()
+	for _, want := range []string{"### Workspace\n", "\n#### Course Introduction\n", "\n##### Reference Guide\n", "\n##### Deleting MyDesk Folder\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Searching Workspace":          "searching-workspace",
+		"Scene's Functions":            "scenes-functions",
+		"**Bold** [Link](/x) - A/B":    "bold-link-ab",
+		"워크스페이스 검색":                    "워크스페이스-검색",
+		"1. Getting_Started (Part 2)!": "1-getting_started-part-2",
+		"???":                          "section",
+	}
+	for in, want := range cases {
+		if got := Slugify(in); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSlugger_Deduplicates(t *testing.T) {
+	sl := NewSlugger()
+	var got []string
+	for _, s := range []string{"Course Introduction", "Course Introduction", "Course Introduction-1", "Course Introduction"} {
+		got = append(got, sl.Slug(s))
+	}
+	if strings.Join(got, " ") != "course-introduction course-introduction-1 course-introduction-1-1 course-introduction-2" {
+		t.Fatalf("unexpected slugs %v", got)
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSONL(&buf, []*Tree{Parse(workspaceDoc(), "")}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("expected 7 records, got %d", len(lines))
+	}
+	var r Record
+	if err := json.Unmarshal([]byte(lines[3]), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != "en/docs/472#workspace/searching-workspace" || r.Level != 4 ||
+		strings.Join(r.Path, " > ") != "Maker > Basic Guide > Workspace > Searching Workspace" {
+		t.Fatalf("unexpected record %+v", r)
+	}
+}
+
+func TestParseAll_DeduplicatesDocumentSlugs(t *testing.T) {
+	docs := []document.Document{
+		{Title: "Scene", Markdown: "# Scene\n\n# Scene\n\nBody."},
+		{Title: "Scene", Markdown: "# Scene\n\nOther."},
+	}
+	trees := ParseAll(docs)
+	if trees[0].Root().Anchor != "scene" || trees[1].Root().Anchor != "scene-1" {
+		t.Fatalf("unexpected document anchors %q, %q", trees[0].Root().Anchor, trees[1].Root().Anchor)
+	}
+	if len(trees[0].Sections) != 2 || trees[0].Sections[1].Anchor != "scene/scene" {
+		t.Fatalf("a section repeating the title must be kept: %+v", trees[0].Sections)
+	}
+}
@@ -0,0 +1,246 @@
+// Package server serves the crawled corpus over HTTP: a JSON API for tools
+// and bots, llms.txt / llms-full.txt, and simple rendered pages for browsing.
+// Every response carries a content-derived ETag and honours conditional
+// requests.
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"maplestory-world-llms-txt/internal/convert"
+	"maplestory-world-llms-txt/internal/corpus"
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/llmstxt"
+)
+
+// Server is an http.Handler over a corpus.
+type Server struct {
+	corpus   *corpus.Corpus
+	loadedAt time.Time
+	mux      *http.ServeMux
+	llms     []byte
+	llmsFull []byte
+	logger   *slog.Logger
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithLogger sets the request logger. nil keeps slog.Default().
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Server) {
+		if l != nil {
+			s.logger = l
+		}
+	}
+}
+
+// New constructs a Server over c.
+func New(c *corpus.Corpus, opts ...Option) *Server {
+	s := &Server{
+		corpus:   c,
+		loadedAt: time.Now().UTC().Truncate(time.Second),
+		mux:      http.NewServeMux(),
+		llms:     []byte(llmstxt.Index(c.Docs)),
+		llmsFull: []byte(llmstxt.Full(c.Docs)),
+		logger:   slog.Default(),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+
+	s.mux.HandleFunc("GET /api/docs", s.handleDocs)
+	s.mux.HandleFunc("GET /api/docs/{id...}", s.handleDoc)
+	s.mux.HandleFunc("GET /api/search", s.handleSearch)
+	s.mux.HandleFunc("GET /api/classes/{name}", s.handleClass)
+	s.mux.HandleFunc("GET /llms.txt", s.handleText(s.llms))
+	s.mux.HandleFunc("GET /llms-full.txt", s.handleText(s.llmsFull))
+	s.mux.HandleFunc("GET /docs/{id...}", s.handlePage)
+	s.mux.HandleFunc("GET /{$}", s.handleHome)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.mux.ServeHTTP(rec, r)
+	s.logger.Debug("http_request",
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", rec.status),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+type docSummary struct {
+	ID    string        `json:"id"`
+	Title string        `json:"title"`
+	URL   string        `json:"url"`
+	Lang  string        `json:"lang"`
+	Kind  document.Kind `json:"kind"`
+}
+
+func summarize(d document.Document) docSummary {
+	return docSummary{ID: d.ID, Title: d.Title, URL: d.URL, Lang: d.Lang, Kind: d.Kind}
+}
+
+func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
+	lang, kind := r.URL.Query().Get("lang"), r.URL.Query().Get("kind")
+	list := make([]docSummary, 0, len(s.corpus.Docs))
+	for _, d := range s.corpus.Docs {
+		if (lang == "" || d.Lang == lang) && (kind == "" || string(d.Kind) == kind) {
+			list = append(list, summarize(d))
+		}
+	}
+	s.writeJSON(w, r, list)
+}
+
+func (s *Server) handleDoc(w http.ResponseWriter, r *http.Request) {
+	d, ok := s.corpus.Doc(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "document not found")
+		return
+	}
+	s.writeJSON(w, r, struct {
+		docSummary
+		Markdown string `json:"markdown"`
+		HTML     string `json:"html"`
+	}{summarize(d), d.Markdown, d.HTML})
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "missing q parameter")
+		return
+	}
+	limit := 10
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = n
+	}
+	s.writeJSON(w, r, s.corpus.Search(query, q.Get("lang"), limit))
+}
+
+func (s *Server) handleClass(w http.ResponseWriter, r *http.Request) {
+	cls, ok := s.corpus.Class(r.URL.Query().Get("lang"), r.PathValue("name"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "class not found")
+		return
+	}
+	s.writeJSON(w, r, cls)
+}
+
+func (s *Server) handleText(body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.serveBytes(w, r, "text/plain; charset=utf-8", body)
+	}
+}
+
+var homeTmpl = template.Must(template.New("home").Parse(`<!doctype html>
+<html><head><meta charset="utf-8"><title>MapleStory Worlds Documentation</title></head>
+<body>
+<h1>MapleStory Worlds Documentation</h1>
+<form action="/api/search"><input name="q" placeholder="Search"> <button>Search</button></form>
+<p><a href="/llms.txt">llms.txt</a> · <a href="/llms-full.txt">llms-full.txt</a> · <a href="/api/docs">/api/docs</a></p>
+<ul>{{range .}}<li><a href="/docs/{{.ID}}">{{.Title}}</a> <small>{{.Lang}} · {{.Kind}}</small></li>
+{{end}}</ul>
+</body></html>
+`))
+
+var pageTmpl = template.Must(template.New("page").Parse(`<!doctype html>
+<html lang="{{.Lang}}"><head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body>
+<p><a href="/">Index</a> · <a href="{{.URL}}">Source</a> · <a href="/api/docs/{{.ID}}">JSON</a></p>
+<article>{{.HTML}}</article>
+</body></html>
+`))
+
+func (s *Server) handleHome(w http.ResponseWriter, r *http.Request) {
+	list := make([]docSummary, 0, len(s.corpus.Docs))
+	for _, d := range s.corpus.Docs {
+		list = append(list, summarize(d))
+	}
+	s.writeTemplate(w, r, homeTmpl, list)
+}
+
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	d, ok := s.corpus.Doc(r.PathValue("id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	// The captured HTML comes from another origin; only its sanitized form
+	// may run under ours.
+	body, err := convert.Sanitize(d.HTML)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeTemplate(w, r, pageTmpl, struct {
+		docSummary
+		HTML template.HTML
+	}{summarize(d), template.HTML(body)})
+}
+
+func (s *Server) writeTemplate(w http.ResponseWriter, r *http.Request, t *template.Template, data any) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.serveBytes(w, r, "text/html; charset=utf-8", buf.Bytes())
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.serveBytes(w, r, "application/json; charset=utf-8", append(data, '\n'))
+}
+
+// serveBytes writes body with a strong ETag derived from its content and the
+// corpus load time as Last-Modified. http.ServeContent answers If-None-Match,
+// If-Modified-Since, HEAD and Range requests.
+func (s *Server) serveBytes(w http.ResponseWriter, r *http.Request, contentType string, body []byte) {
+	sum := sha256.Sum256(body)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, "", s.loadedAt, bytes.NewReader(body))
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/corpus"
+	"maplestory-world-llms-txt/internal/document"
+)
+
+func testServer() *Server {
+	return New(corpus.New([]document.Document{
+		{Title: "Workspace", URL: "https://maplestoryworlds-creators.nexon.com/en/docs/?postId=472",
+			HTML: "<h1>Workspace</h1>", Markdown: "# Workspace\n\nSearching Workspace."},
+		{Title: "AIComponent", URL: "https://maplestoryworlds-creators.nexon.com/en/apiReference/Components/AIComponent",
+			HTML: "<h1>AIComponent</h1>", Markdown: "# AIComponent\n\nAI.\n\n# Properties\n\n| boolean IsLegacy |\n| --- |\n| Legacy. |\n"},
+	}))
+}
+
+func get(t *testing.T, h http.Handler, target string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestDocsEndpoints(t *testing.T) {
+	s := testServer()
+
+	rec := get(t, s, "/api/docs?kind=api", nil)
+	var list []docSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != "en/apiReference/Components/AIComponent" {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+
+	rec = get(t, s, "/api/docs/en/docs/472", nil)
+	var doc struct {
+		ID, Title, Markdown, HTML string
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode doc: %v", err)
+	}
+	if doc.Title != "Workspace" || doc.HTML != "<h1>Workspace</h1>" || !strings.HasPrefix(doc.Markdown, "# Workspace") {
+		t.Fatalf("unexpected doc: %+v", doc)
+	}
+
+	if rec := get(t, s, "/api/docs/en/docs/1", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestSearchAndClasses(t *testing.T) {
+	s := testServer()
+	rec := get(t, s, "/api/search?q=workspace&lang=en", nil)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"id": "en/docs/472"`) {
+		t.Fatalf("unexpected search response %d: %s", rec.Code, rec.Body)
+	}
+	if rec := get(t, s, "/api/search", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without q, got %d", rec.Code)
+	}
+	rec = get(t, s, "/api/classes/aicomponent", nil)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"name": "IsLegacy"`) {
+		t.Fatalf("unexpected class response %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestLLMSTxtAndPages(t *testing.T) {
+	s := testServer()
+	if rec := get(t, s, "/llms.txt", nil); !strings.Contains(rec.Body.String(), "- [Workspace](") {
+		t.Fatalf("unexpected llms.txt: %s", rec.Body)
+	}
+	if rec := get(t, s, "/llms-full.txt", nil); !strings.Contains(rec.Body.String(), "# AIComponent") {
+		t.Fatalf("unexpected llms-full.txt: %s", rec.Body)
+	}
+	rec := get(t, s, "/docs/en/docs/472", nil)
+	if !strings.Contains(rec.Body.String(), "<article><h1>Workspace</h1></article>") {
+		t.Fatalf("unexpected page: %s", rec.Body)
+	}
+	if rec := get(t, s, "/", nil); !strings.Contains(rec.Body.String(), `href="/docs/en/docs/472"`) {
+		t.Fatalf("unexpected home page: %s", rec.Body)
+	}
+}
+
+func TestPageSanitizesCapturedHTML(t *testing.T) {
+	s := New(corpus.New([]document.Document{
+		{Title: "Workspace", URL: "https://maplestoryworlds-creators.nexon.com/en/docs/?postId=472",
+			HTML: `<h1 onclick="steal()">Workspace</h1><script>steal()</script><a href="javascript:steal()">x</a>`},
+	}))
+	body := get(t, s, "/docs/en/docs/472", nil).Body.String()
+	if !strings.Contains(body, "<article><h1>Workspace</h1><a>x</a></article>") || strings.Contains(body, "steal") {
+		t.Fatalf("page must serve sanitized HTML: %s", body)
+	}
+}
+
+func TestConditionalRequests(t *testing.T) {
+	s := testServer()
+	rec := get(t, s, "/api/docs", nil)
+	etag := rec.Header().Get("ETag")
+	if etag == "" || rec.Header().Get("Last-Modified") == "" {
+		t.Fatalf("missing validators: %v", rec.Header())
+	}
+	if rec := get(t, s, "/api/docs", map[string]string{"If-None-Match": etag}); rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for matching ETag, got %d", rec.Code)
+	}
+	if rec := get(t, s, "/api/docs", map[string]string{"If-None-Match": `"other"`}); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for stale ETag, got %d", rec.Code)
+	}
+	if rec := get(t, s, "/api/docs?lang=ko", nil); rec.Header().Get("ETag") == etag {
+		t.Fatalf("different bodies should have different ETags")
+	}
+}
@@ -0,0 +1,289 @@
+package sqlexport
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"maplestory-world-llms-txt/internal/apiref"
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/section"
+
+	_ "modernc.org/sqlite" // registers the cgo-free "sqlite" driver
+)
+
+// Stats counts the rows an export wrote.
+type Stats struct {
+	Documents  int
+	Sections   int
+	Classes    int
+	Members    int
+	Parameters int
+	Links      int
+}
+
+// Create writes docs into a new SQLite database at path, replacing any file
+// already there. The database is built next to path and renamed into place,
+// so readers never see a partial export.
+func Create(ctx context.Context, path string, docs []document.Document) (Stats, error) {
+	tmp := path + ".tmp"
+	if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
+		return Stats{}, err
+	}
+	db, err := sql.Open("sqlite", tmp)
+	if err != nil {
+		return Stats{}, err
+	}
+	stats, err := Export(ctx, db, docs)
+	if cerr := db.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return Stats{}, err
+	}
+	return stats, os.Rename(tmp, path)
+}
+
+// Export creates the schema in an empty database and writes docs into it in
+// one transaction. Documents sharing an ID keep the first occurrence.
+func Export(ctx context.Context, db *sql.DB, docs []document.Document) (Stats, error) {
+	// A connection of its own keeps the pragmas and the transaction together
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return Stats{}, err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = ON"); err != nil {
+		return Stats{}, err
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return Stats{}, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, Schema); err != nil {
+		return Stats{}, fmt.Errorf("create schema: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "PRAGMA user_version = "+strconv.Itoa(SchemaVersion)); err != nil {
+		return Stats{}, err
+	}
+	w := &writer{ctx: ctx, tx: tx, stmts: make(map[string]*sql.Stmt)}
+	defer w.close()
+	if err := w.exec(`INSERT INTO meta (key, value) VALUES ('schema_version', ?), ('generator', 'maplestory-world-llms-txt')`, SchemaVersion); err != nil {
+		return Stats{}, err
+	}
+	if err := w.write(dedupe(docs)); err != nil {
+		return Stats{}, err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO sections_fts (sections_fts) VALUES ('rebuild')`); err != nil {
+		return Stats{}, fmt.Errorf("build full-text index: %w", err)
+	}
+	return w.stats, tx.Commit()
+}
+
+// Version returns the schema version of an exported database.
+func Version(ctx context.Context, db *sql.DB) (int, error) {
+	var v int
+	err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&v)
+	return v, err
+}
+
+// dedupe drops documents whose ID was already seen, deriving missing IDs from
+// the URL like the corpus does.
+func dedupe(docs []document.Document) []document.Document {
+	seen := make(map[string]bool)
+	var out []document.Document
+	for _, d := range docs {
+		if d.ID == "" {
+			d.ID, d.Lang, d.Kind = document.IDOf(d.URL), document.LangOf(d.URL), document.KindOf(d.URL)
+		}
+		if !seen[d.ID] {
+			seen[d.ID] = true
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// sectionTrees splits docs into sections, numbering anchors per language and
+// kind, which is how the crawl splits the corpus into files.
+func sectionTrees(docs []document.Document) []*section.Tree {
+	type file struct {
+		lang string
+		kind document.Kind
+	}
+	var (
+		files  []file
+		byFile = make(map[file][]int)
+	)
+	for i, d := range docs {
+		f := file{d.Lang, d.Kind}
+		if _, ok := byFile[f]; !ok {
+			files = append(files, f)
+		}
+		byFile[f] = append(byFile[f], i)
+	}
+	trees := make([]*section.Tree, len(docs))
+	for _, f := range files {
+		idx := byFile[f]
+		group := make([]document.Document, len(idx))
+		for j, i := range idx {
+			group[j] = docs[i]
+		}
+		for j, t := range section.ParseAll(group) {
+			trees[idx[j]] = t
+		}
+	}
+	return trees
+}
+
+// writer inserts rows with prepared statements, assigning row IDs in corpus
+// order.
+type writer struct {
+	ctx   context.Context
+	tx    *sql.Tx
+	stmts map[string]*sql.Stmt
+	stats Stats
+}
+
+func (w *writer) exec(query string, args ...any) error {
+	st, ok := w.stmts[query]
+	if !ok {
+		var err error
+		if st, err = w.tx.PrepareContext(w.ctx, query); err != nil {
+			return fmt.Errorf("prepare %q: %w", query, err)
+		}
+		w.stmts[query] = st
+	}
+	_, err := st.ExecContext(w.ctx, args...)
+	return err
+}
+
+func (w *writer) close() {
+	for _, st := range w.stmts {
+		st.Close()
+	}
+}
+
+func (w *writer) write(docs []document.Document) error {
+	rows := make(map[string]int64, len(docs))
+	for i, d := range docs {
+		rows[d.ID] = int64(i + 1)
+	}
+	for _, d := range docs {
+		if err := w.document(rows[d.ID], d); err != nil {
+			return fmt.Errorf("document %s: %w", d.ID, err)
+		}
+		w.stats.Documents++
+	}
+	var sectionRow int64
+	for i, t := range sectionTrees(docs) {
+		d := docs[i]
+		first := sectionRow + 1
+		for j, s := range t.Sections {
+			sectionRow++
+			var parent any
+			if s.Parent >= 0 {
+				parent = first + int64(s.Parent)
+			}
+			if err := w.exec(`INSERT INTO sections (id, document_id, parent_id, position, level, title, anchor, body) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+				sectionRow, rows[d.ID], parent, j, s.Level, s.Title, s.Anchor, s.Body); err != nil {
+				return fmt.Errorf("document %s: section %q: %w", d.ID, s.Title, err)
+			}
+			w.stats.Sections++
+			for _, l := range Links(s.Body, d.URL) {
+				var to any
+				if row, ok := target(l.URL, d.Lang, rows); ok {
+					to = row
+				}
+				w.stats.Links++
+				if err := w.exec(`INSERT INTO links (id, section_id, text, url, image, target_id) VALUES (?, ?, ?, ?, ?, ?)`,
+					w.stats.Links, sectionRow, l.Text, l.URL, l.Image, to); err != nil {
+					return fmt.Errorf("document %s: link %s: %w", d.ID, l.URL, err)
+				}
+			}
+		}
+	}
+	for _, d := range docs {
+		if d.Kind != document.KindAPI {
+			continue
+		}
+		if c, ok := apiref.Parse(d.URL, d.Markdown); ok {
+			if err := w.class(rows[d.ID], c); err != nil {
+				return fmt.Errorf("class %s: %w", c.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (w *writer) document(row int64, d document.Document) error {
+	crumb := d.Breadcrumb
+	if crumb == nil {
+		crumb = []string{}
+	}
+	breadcrumb, err := json.Marshal(crumb)
+	if err != nil {
+		return err
+	}
+	var fetched any
+	if !d.Fetch.FetchedAt.IsZero() {
+		fetched = d.Fetch.FetchedAt.UTC().Format(time.RFC3339)
+	}
+	return w.exec(`INSERT INTO documents (id, doc_id, url, lang, kind, title, breadcrumb, markdown, fetched_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		row, d.ID, d.URL, d.Lang, string(d.Kind), d.Title, string(breadcrumb), d.Markdown, fetched)
+}
+
+func (w *writer) class(doc int64, c *apiref.Class) error {
+	w.stats.Classes++
+	id := w.stats.Classes
+	if err := w.exec(`INSERT INTO classes (id, document_id, name, description, examples) VALUES (?, ?, ?, ?, ?)`,
+		id, doc, c.Name, c.Description, c.Examples); err != nil {
+		return err
+	}
+	for _, b := range c.Badges {
+		if err := w.exec(`INSERT OR IGNORE INTO class_badges (class_id, badge) VALUES (?, ?)`, id, b); err != nil {
+			return err
+		}
+	}
+	for i, base := range c.Bases {
+		if err := w.exec(`INSERT INTO class_bases (class_id, position, name) VALUES (?, ?, ?)`, id, i, base); err != nil {
+			return err
+		}
+	}
+	for i, m := range c.Members() {
+		w.stats.Members++
+		member := w.stats.Members
+		if err := w.exec(`INSERT INTO members (id, class_id, position, kind, name, type, type_url, description, inherited_from, signature) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			member, id, i, string(m.Kind), m.Name, m.Type.Name, nullable(m.Type.URL), m.Description, nullable(m.InheritedFrom), m.Signature); err != nil {
+			return fmt.Errorf("member %s: %w", m.Name, err)
+		}
+		for _, b := range m.Badges {
+			if err := w.exec(`INSERT OR IGNORE INTO member_badges (member_id, badge) VALUES (?, ?)`, member, b); err != nil {
+				return err
+			}
+		}
+		for j, p := range m.Params {
+			w.stats.Parameters++
+			if err := w.exec(`INSERT INTO parameters (id, member_id, position, name, type, type_url, default_value) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				w.stats.Parameters, member, j, p.Name, p.Type.Name, nullable(p.Type.URL), nullable(p.Default)); err != nil {
+				return fmt.Errorf("member %s: parameter %s: %w", m.Name, p.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// nullable maps the empty string to NULL.
+func nullable(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
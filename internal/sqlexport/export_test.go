@@ -0,0 +1,151 @@
+package sqlexport
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"maplestory-world-llms-txt/internal/document"
+)
+
+const classPage = `# AIChaseComponent
+
+Chases a target.
+
+# Properties
+
+| float DetectionRange |
+| --- |
+| Range of trace detection. |
+
+##### inherited from Component:
+
+| boolean Enable [Sync] [HideFromInspector] |
+| --- |
+| Checks whether Component is activated or not. |
+
+# Methods
+
+| void SetTarget([Entity](https://mod-developers.nexon.com/apiReference/Misc/Entity) targetEntity, [number](https://mod-developers.nexon.com/apiReference/Lua/number) delay = 0) |
+| --- |
+| Sets the target. See [Workspace](https://mod-developers.nexon.com/docs?postId=1). |
+`
+
+func sampleDocs() []document.Document {
+	guide := document.New("Workspace", "https://maplestoryworlds-creators.nexon.com/en/docs?postId=1", "")
+	guide.Breadcrumb = []string{"Maker", "Basic Guide"}
+	guide.Markdown = "# Workspace\n\nOpen the **Hierarchy** panel.\n\n## Panels\n\nSee [AIChaseComponent](/en/apiReference/Components/AIChaseComponent)."
+	guide.Fetch.FetchedAt = time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	api := document.New("AIChaseComponent", "https://maplestoryworlds-creators.nexon.com/en/apiReference/Components/AIChaseComponent", "")
+	api.Markdown = classPage
+	return []document.Document{guide, api, guide}
+}
+
+func export(t *testing.T) (*sql.DB, Stats) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "corpus.db")
+	stats, err := Create(context.Background(), path, sampleDocs())
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, stats
+}
+
+func column(t *testing.T, db *sql.DB, query string, args ...any) []string {
+	t.Helper()
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	defer rows.Close()
+	var out []string
+	for rows.Next() {
+		var s sql.NullString
+		if err := rows.Scan(&s); err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, s.String)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestExport_WritesNormalizedTables(t *testing.T) {
+	db, stats := export(t)
+
+	want := Stats{Documents: 2, Sections: 2 + 4, Classes: 1, Members: 3, Parameters: 2, Links: 4}
+	if stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+	if v, err := Version(context.Background(), db); err != nil || v != SchemaVersion {
+		t.Errorf("Version = %d, %v; want %d", v, err, SchemaVersion)
+	}
+
+	checks := []struct {
+		query string
+		want  []string
+	}{
+		{`SELECT doc_id || ' ' || breadcrumb || ' ' || ifnull(fetched_at, '-') FROM documents ORDER BY id`,
+			[]string{`en/docs/1 ["Maker","Basic Guide"] 2025-03-01T12:30:00Z`, "en/apiReference/Components/AIChaseComponent [] -"}},
+		{`SELECT s.anchor || ' ' || s.level || ' ' || ifnull(p.anchor, '-') FROM sections s LEFT JOIN sections p ON p.id = s.parent_id WHERE s.document_id = 1 ORDER BY s.position`,
+			[]string{"workspace 3 -", "workspace/panels 4 workspace"}},
+		{`SELECT kind || ' ' || name || ' ' || ifnull(inherited_from, '-') FROM members ORDER BY position`,
+			[]string{"property DetectionRange -", "property Enable Component", "method SetTarget -"}},
+		{`SELECT m.name || ' ' || b.badge FROM member_badges b JOIN members m ON m.id = b.member_id ORDER BY b.badge`,
+			[]string{"Enable HideFromInspector", "Enable Sync"}},
+		{`SELECT name FROM class_bases`, []string{"Component"}},
+		// The example from the package documentation
+		{`SELECT c.name || '.' || m.name FROM parameters p JOIN members m ON m.id = p.member_id JOIN classes c ON c.id = m.class_id
+			WHERE m.kind = 'method' AND p.type = 'Entity' AND m.inherited_from IS NULL`,
+			[]string{"AIChaseComponent.SetTarget"}},
+		{`SELECT name || ' ' || type || ' ' || ifnull(default_value, '-') FROM parameters ORDER BY position`,
+			[]string{"targetEntity Entity -", "delay number 0"}},
+		{`SELECT l.text || ' ' || ifnull(d.title, '-') FROM links l LEFT JOIN documents d ON d.id = l.target_id WHERE l.image = 0 ORDER BY l.id`,
+			[]string{"AIChaseComponent AIChaseComponent", "Entity -", "number -", "Workspace Workspace"}},
+	}
+	for _, c := range checks {
+		if got := column(t, db, c.query); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s\n got %q\nwant %q", c.query, got, c.want)
+		}
+	}
+	if got := column(t, db, `PRAGMA foreign_key_check`); len(got) != 0 {
+		t.Errorf("dangling references: %q", got)
+	}
+}
+
+func TestExport_FullTextIndex(t *testing.T) {
+	db, _ := export(t)
+	got := column(t, db, `SELECT s.anchor FROM sections_fts f JOIN sections s ON s.id = f.rowid WHERE sections_fts MATCH ? ORDER BY rank`, "hierarchy")
+	if !reflect.DeepEqual(got, []string{"workspace"}) {
+		t.Errorf("MATCH hierarchy = %q", got)
+	}
+	got = column(t, db, `SELECT s.anchor FROM sections_fts f JOIN sections s ON s.id = f.rowid WHERE sections_fts MATCH ?`, "title:methods")
+	if !reflect.DeepEqual(got, []string{"aichasecomponent/methods"}) {
+		t.Errorf("MATCH title:methods = %q", got)
+	}
+}
+
+func TestCreate_ReplacesExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "corpus.db")
+	ctx := context.Background()
+	if _, err := Create(ctx, path, sampleDocs()); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := Create(ctx, path, sampleDocs()[:1])
+	if err != nil {
+		t.Fatalf("second Create: %v", err)
+	}
+	if stats.Documents != 1 {
+		t.Errorf("expected 1 document, got %d", stats.Documents)
+	}
+}
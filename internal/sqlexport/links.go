@@ -0,0 +1,73 @@
+package sqlexport
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/markdown"
+)
+
+// Link is a Markdown link or image found in a section body.
+type Link struct {
+	Text  string
+	URL   string
+	Image bool
+}
+
+var (
+	// linkRe matches [text](url) and ![alt](url); the text may hold one level
+	// of brackets, as in [![badge](img)](url).
+	linkRe     = regexp.MustCompile(`(!?)\[((?:[^\[\]]|\[[^\[\]]*\])*)\]\(\s*<?([^)\s>]*)>?(?:\s+"[^"]*")?\s*\)`)
+	codeSpanRe = regexp.MustCompile("`+[^`]*`+")
+)
+
+// Links returns the links and images of a Markdown body in order, skipping
+// fenced code and code spans. URLs are resolved against base when it parses;
+// same-page fragment links are kept as written.
+func Links(md, base string) []Link {
+	baseURL, _ := url.Parse(base)
+	var out []Link
+	var scan func(s string)
+	scan = func(s string) {
+		for _, m := range linkRe.FindAllStringSubmatch(s, -1) {
+			l := Link{Text: m[2], URL: m[3], Image: m[1] == "!"}
+			if baseURL != nil && l.URL != "" && !strings.HasPrefix(l.URL, "#") {
+				if u, err := baseURL.Parse(l.URL); err == nil {
+					l.URL = u.String()
+				}
+			}
+			out = append(out, l)
+			// Images wrapped in the link follow it
+			if strings.Contains(l.Text, "](") {
+				scan(l.Text)
+			}
+		}
+	}
+	for _, b := range markdown.Parse(md) {
+		if b.Kind == markdown.Code {
+			continue
+		}
+		scan(codeSpanRe.ReplaceAllString(b.Text(), ""))
+	}
+	return out
+}
+
+// target returns the row of the document an absolute link leads to, given
+// the rows by corpus ID, and false when it leads outside the corpus. Links to
+// the site's former host and links without the language (/docs?postId=54)
+// resolve too.
+func target(link, lang string, ids map[string]int64) (int64, bool) {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != "http" && u.Scheme != "https" || !strings.HasSuffix(u.Hostname(), "nexon.com") {
+		return 0, false
+	}
+	u.Fragment = ""
+	id := document.IDOf(u.String())
+	if row, ok := ids[id]; ok {
+		return row, true
+	}
+	row, ok := ids[lang+"/"+id]
+	return row, ok
+}
@@ -0,0 +1,41 @@
+package sqlexport
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLinks(t *testing.T) {
+	md := "See [Model](/docs?postId=55) and [top](#top).\n\n" +
+		"[![badge](https://img.shields.io/b.svg)](https://example.com/x \"title\")\n\n" +
+		"`[not](a-link)`\n\n```lua\n-- [nor](this)\n```\n\n| [EntityRef](https://mod-developers.nexon.com/apiReference/Misc/EntityRef) Ref |"
+	got := Links(md, "https://maplestoryworlds-creators.nexon.com/en/docs?postId=1")
+	want := []Link{
+		{Text: "Model", URL: "https://maplestoryworlds-creators.nexon.com/docs?postId=55"},
+		{Text: "top", URL: "#top"},
+		{Text: "![badge](https://img.shields.io/b.svg)", URL: "https://example.com/x"},
+		{Text: "badge", URL: "https://img.shields.io/b.svg", Image: true},
+		{Text: "EntityRef", URL: "https://mod-developers.nexon.com/apiReference/Misc/EntityRef"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Links =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestTarget(t *testing.T) {
+	ids := map[string]int64{"en/docs/55": 1, "en/apiReference/Misc/EntityRef": 2}
+	cases := map[string]int64{
+		"https://maplestoryworlds-creators.nexon.com/en/docs?postId=55": 1,
+		"https://maplestoryworlds-creators.nexon.com/docs?postId=55#x":  1,
+		"https://mod-developers.nexon.com/apiReference/Misc/EntityRef":  2,
+		"https://maplestoryworlds-creators.nexon.com/ko/docs?postId=55": 0,
+		"https://example.com/en/docs?postId=55":                         0,
+		"#top":                                                          0,
+	}
+	for link, want := range cases {
+		got, ok := target(link, "en", ids)
+		if got != want || ok != (want != 0) {
+			t.Errorf("target(%q) = %d, %v; want %d", link, got, ok, want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Package sqlexport writes a corpus into a normalized SQLite database for
+// ad-hoc querying: documents, their sections, the API model parsed from the
+// apiReference pages (classes, members, parameters) and the links between
+// pages, with an FTS5 full-text index over the sections. It uses a pure-Go
+// SQLite driver, so it builds without cgo.
+//
+// For example, every method taking an Entity:
+//
+//	SELECT c.name, m.name, m.signature
+//	FROM parameters p
+//	JOIN members m ON m.id = p.member_id
+//	JOIN classes c ON c.id = m.class_id
+//	WHERE m.kind = 'method' AND p.type = 'Entity' AND m.inherited_from IS NULL;
+package sqlexport
+
+// SchemaVersion is stored in PRAGMA user_version and in the meta table.
+// Bump it whenever a table or column changes meaning, is added or is removed,
+// so queries written against an older database can detect the difference.
+const SchemaVersion = 1
+
+// Schema creates the tables of an empty database. Row IDs follow the order of
+// the corpus, so exporting the same corpus yields the same rows.
+const Schema = `
+-- meta holds key/value facts about the export: schema_version and generator.
+CREATE TABLE meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+
+-- documents holds one row per crawled page, in corpus order.
+CREATE TABLE documents (
+	id         INTEGER PRIMARY KEY,
+	doc_id     TEXT NOT NULL UNIQUE, -- corpus ID, e.g. en/docs/472
+	url        TEXT NOT NULL,
+	lang       TEXT NOT NULL,        -- en, ko, ...
+	kind       TEXT NOT NULL,        -- reference or api
+	title      TEXT NOT NULL,
+	breadcrumb TEXT NOT NULL,        -- JSON array of the nav labels above the page
+	markdown   TEXT NOT NULL,
+	fetched_at TEXT                  -- RFC 3339; NULL when the corpus has no fetch time
+);
+
+-- sections splits every document at its headings. The root section
+-- (position 0) is titled after the document and holds the content before the
+-- first heading. Anchors are unique within a corpus file (one language and
+-- kind), matching the concatenated Markdown.
+CREATE TABLE sections (
+	id          INTEGER PRIMARY KEY,
+	document_id INTEGER NOT NULL REFERENCES documents(id),
+	parent_id   INTEGER REFERENCES sections(id), -- NULL for the root
+	position    INTEGER NOT NULL,                -- order within the document
+	level       INTEGER NOT NULL,                -- normalized heading level, 1-6
+	title       TEXT NOT NULL,
+	anchor      TEXT NOT NULL,
+	body        TEXT NOT NULL                    -- Markdown without heading or subsections
+);
+CREATE INDEX sections_document ON sections(document_id);
+
+-- classes holds the API model of every apiReference page that documents
+-- properties, methods or events.
+CREATE TABLE classes (
+	id          INTEGER PRIMARY KEY,
+	document_id INTEGER NOT NULL UNIQUE REFERENCES documents(id),
+	name        TEXT NOT NULL,
+	description TEXT NOT NULL,
+	examples    TEXT NOT NULL
+);
+CREATE INDEX classes_name ON classes(name);
+
+CREATE TABLE class_badges (
+	class_id INTEGER NOT NULL REFERENCES classes(id),
+	badge    TEXT NOT NULL,
+	PRIMARY KEY (class_id, badge)
+);
+
+-- class_bases lists the classes a class inherits members from, nearest first.
+CREATE TABLE class_bases (
+	class_id INTEGER NOT NULL REFERENCES classes(id),
+	position INTEGER NOT NULL,
+	name     TEXT NOT NULL,
+	PRIMARY KEY (class_id, position)
+);
+
+-- members holds properties, methods and events as listed on the class page,
+-- inherited ones included with the class they come from.
+CREATE TABLE members (
+	id             INTEGER PRIMARY KEY,
+	class_id       INTEGER NOT NULL REFERENCES classes(id),
+	position       INTEGER NOT NULL, -- page order: properties, methods, events
+	kind           TEXT NOT NULL,    -- property, method or event
+	name           TEXT NOT NULL,
+	type           TEXT NOT NULL,    -- property type, return type or event type
+	type_url       TEXT,
+	description    TEXT NOT NULL,
+	inherited_from TEXT,             -- NULL for members declared on the class
+	signature      TEXT NOT NULL
+);
+CREATE INDEX members_class ON members(class_id);
+CREATE INDEX members_name ON members(name);
+
+CREATE TABLE member_badges (
+	member_id INTEGER NOT NULL REFERENCES members(id),
+	badge     TEXT NOT NULL, -- ReadOnly, Sync, ServerOnly, ...
+	PRIMARY KEY (member_id, badge)
+);
+
+CREATE TABLE parameters (
+	id            INTEGER PRIMARY KEY,
+	member_id     INTEGER NOT NULL REFERENCES members(id),
+	position      INTEGER NOT NULL,
+	name          TEXT NOT NULL,
+	type          TEXT NOT NULL,
+	type_url      TEXT,
+	default_value TEXT -- NULL when the parameter is required
+);
+CREATE INDEX parameters_member ON parameters(member_id);
+CREATE INDEX parameters_type ON parameters(type);
+
+-- links holds the Markdown links and images of every section, outside code.
+CREATE TABLE links (
+	id          INTEGER PRIMARY KEY,
+	section_id  INTEGER NOT NULL REFERENCES sections(id),
+	text        TEXT NOT NULL,    -- link text or image alt text
+	url         TEXT NOT NULL,    -- absolute when it resolves against the page
+	image       INTEGER NOT NULL, -- 1 for images
+	target_id   INTEGER REFERENCES documents(id) -- NULL outside the corpus
+);
+CREATE INDEX links_section ON links(section_id);
+CREATE INDEX links_target ON links(target_id);
+
+-- sections_fts indexes section titles and bodies; join on rowid = sections.id.
+-- The unicode61 tokenizer splits on spaces and punctuation, so Korean words
+-- match with their particles attached unless queried as prefixes (컴포넌트*).
+CREATE VIRTUAL TABLE sections_fts USING fts5(
+	title, body,
+	content = 'sections', content_rowid = 'id',
+	tokenize = 'unicode61'
+);
+`
@@ -0,0 +1,345 @@
+// Package validate lints crawled and converted documents for the problems
+// that otherwise only surface when someone reads the output: empty pages,
+// missing or duplicated titles, Markdown the converter left half-done, and
+// pages that lost most of their content since the previous crawl.
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+
+	"maplestory-world-llms-txt/internal/document"
+	"maplestory-world-llms-txt/internal/markdown"
+)
+
+// Severity ranks an issue. Errors mean the document is unusable; warnings
+// mean it is probably degraded.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule names the check that produced an issue.
+type Rule string
+
+const (
+	RuleEmptyBody      Rule = "empty-body"
+	RuleShortBody      Rule = "short-body"
+	RuleMissingTitle   Rule = "missing-title"
+	RuleDuplicateTitle Rule = "duplicate-title"
+	RuleUnclosedFence  Rule = "unclosed-fence"
+	RuleBrokenTable    Rule = "broken-table"
+	RuleRawHTML        Rule = "raw-html"
+	RuleShrunk         Rule = "shrunk"
+)
+
+// Issue is a single finding about one document.
+type Issue struct {
+	Rule     Rule     `json:"rule"`
+	Severity Severity `json:"severity"`
+	ID       string   `json:"id"`
+	URL      string   `json:"url"`
+	Title    string   `json:"title,omitempty"`
+	// Line is the 1-based Markdown line the issue refers to, or 0.
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	loc := i.ID
+	if i.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", loc, i.Line)
+	}
+	return fmt.Sprintf("%s: %s [%s] %s", loc, i.Severity, i.Rule, i.Message)
+}
+
+// Validator holds the thresholds used by Check.
+type Validator struct {
+	// MinBodyRunes is the body length, in non-space runes, below which a
+	// document is reported as near-empty.
+	MinBodyRunes int
+	// ShrinkRatio reports documents whose body is shorter than this fraction
+	// of the same document in the previous crawl.
+	ShrinkRatio float64
+	// Previous is the previous crawl, keyed by document ID.
+	Previous map[string]document.Document
+}
+
+// Option configures a Validator.
+type Option func(*Validator)
+
+// WithMinBodyRunes sets the near-empty threshold. Negative values are clamped to 0.
+func WithMinBodyRunes(n int) Option {
+	if n < 0 {
+		n = 0
+	}
+	return func(v *Validator) { v.MinBodyRunes = n }
+}
+
+// WithShrinkRatio sets the shrink threshold. Values outside [0, 1] are
+// clamped; 0 disables the check.
+func WithShrinkRatio(r float64) Option {
+	r = min(max(r, 0), 1)
+	return func(v *Validator) { v.ShrinkRatio = r }
+}
+
+// WithPrevious sets the previous crawl to compare body lengths against.
+func WithPrevious(docs []document.Document) Option {
+	return func(v *Validator) {
+		v.Previous = make(map[string]document.Document, len(docs))
+		for _, d := range docs {
+			v.Previous[d.ID] = d
+		}
+	}
+}
+
+// NewValidator constructs a Validator with sensible defaults (40 runes, 0.5).
+func NewValidator(opts ...Option) *Validator {
+	v := &Validator{MinBodyRunes: 40, ShrinkRatio: 0.5}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(v)
+		}
+	}
+	return v
+}
+
+// Check lints docs and returns the issues ordered by document, then line.
+// Markdown rules only apply to documents that have been converted.
+func (v *Validator) Check(docs []document.Document) []Issue {
+	var issues []Issue
+	for _, d := range docs {
+		issues = append(issues, v.checkDoc(d)...)
+	}
+	issues = append(issues, duplicateTitles(docs)...)
+	sort.SliceStable(issues, func(a, b int) bool {
+		if issues[a].ID != issues[b].ID {
+			return issues[a].ID < issues[b].ID
+		}
+		return issues[a].Line < issues[b].Line
+	})
+	return issues
+}
+
+// HasErrors reports whether any issue is an error.
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *Validator) checkDoc(d document.Document) []Issue {
+	var issues []Issue
+	add := func(rule Rule, sev Severity, line int, format string, args ...any) {
+		issues = append(issues, Issue{
+			Rule: rule, Severity: sev, ID: d.ID, URL: d.URL, Title: d.Title,
+			Line: line, Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if strings.TrimSpace(d.Title) == "" {
+		add(RuleMissingTitle, SeverityError, 0, "document has no title")
+	}
+
+	n := BodyLength(d)
+	switch {
+	case n == 0:
+		add(RuleEmptyBody, SeverityError, 0, "document body is empty")
+	case n < v.MinBodyRunes:
+		add(RuleShortBody, SeverityWarning, 0, "document body has only %d characters", n)
+	}
+	if prev, ok := v.Previous[d.ID]; ok && v.ShrinkRatio > 0 {
+		if p := BodyLength(prev); p >= v.MinBodyRunes && float64(n) < float64(p)*v.ShrinkRatio {
+			add(RuleShrunk, SeverityWarning, 0, "body shrank from %d to %d characters since the previous crawl", p, n)
+		}
+	}
+
+	if d.Markdown == "" {
+		return issues
+	}
+	lines, cursor := strings.Split(d.Markdown, "\n"), 0
+	for _, b := range markdown.Parse(d.Markdown) {
+		start := lineOf(lines, b.Lines[0], &cursor)
+		switch b.Kind {
+		case markdown.Code:
+			if !b.Closed {
+				add(RuleUnclosedFence, SeverityError, start, "code fence is never closed")
+			}
+		case markdown.Table:
+			if msg := checkTable(b.Lines); msg != "" {
+				add(RuleBrokenTable, SeverityWarning, start, "%s", msg)
+			}
+		default:
+			if tag := rawTag.FindString(b.Text()); tag != "" && !inCodeSpan(b.Text(), tag) {
+				add(RuleRawHTML, SeverityWarning, start, "unconverted HTML %s", tag)
+			}
+		}
+	}
+	return issues
+}
+
+// lineOf returns the 1-based number of the first line at or after *cursor
+// that equals first, and advances the cursor past it.
+func lineOf(lines []string, first string, cursor *int) int {
+	for i := *cursor; i < len(lines); i++ {
+		if lines[i] == first {
+			*cursor = i + 1
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// duplicateTitles reports documents of the same language that share a title
+// but not a URL.
+func duplicateTitles(docs []document.Document) []Issue {
+	type key struct{ lang, title string }
+	first := make(map[key]document.Document)
+	var issues []Issue
+	for _, d := range docs {
+		t := strings.TrimSpace(d.Title)
+		if t == "" {
+			continue
+		}
+		k := key{d.Lang, strings.ToLower(t)}
+		orig, ok := first[k]
+		if !ok {
+			first[k] = d
+			continue
+		}
+		if orig.URL == d.URL {
+			continue
+		}
+		issues = append(issues, Issue{
+			Rule: RuleDuplicateTitle, Severity: SeverityWarning,
+			ID: d.ID, URL: d.URL, Title: d.Title,
+			Message: fmt.Sprintf("title %q is also used by %s", d.Title, orig.ID),
+		})
+	}
+	return issues
+}
+
+// BodyLength returns the number of non-space runes in a document's body: its
+// Markdown without the title heading when converted, otherwise the text of
+// its HTML.
+func BodyLength(d document.Document) int {
+	text := d.Markdown
+	if text == "" {
+		text = htmlText(d.HTML)
+	} else {
+		var b strings.Builder
+		for _, blk := range markdown.Parse(text) {
+			if blk.Kind == markdown.Heading && blk.Level == 1 && blk.Title == strings.TrimSpace(d.Title) {
+				continue
+			}
+			b.WriteString(blk.Text())
+		}
+		text = b.String()
+	}
+	n := 0
+	for _, f := range strings.Fields(text) {
+		n += utf8.RuneCountInString(f)
+	}
+	return n
+}
+
+func htmlText(s string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return b.String()
+		case html.TextToken:
+			b.Write(z.Text())
+			b.WriteByte(' ')
+		}
+	}
+}
+
+// rawTag matches the HTML tags mdream is expected to convert.
+var rawTag = regexp.MustCompile(`(?i)</?(?:div|span|p|br|img|a|table|thead|tbody|tr|td|th|ul|ol|li|h[1-6]|strong|em|b|i|code|pre|blockquote|figure|figcaption)(?:\s[^<>]*)?/?>`)
+
+// inCodeSpan reports whether every occurrence of tag in text sits inside an
+// inline code span.
+func inCodeSpan(text, tag string) bool {
+	inCode := false
+	for i := 0; i < len(text); i++ {
+		if text[i] == '`' {
+			inCode = !inCode
+			continue
+		}
+		if !inCode && strings.HasPrefix(text[i:], tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkTable returns a description of what is wrong with a pipe table, or ""
+// when it is well formed: a header row, a delimiter row and rows with the
+// header's cell count.
+func checkTable(rows []string) string {
+	if len(rows) < 2 {
+		return "table has no delimiter row"
+	}
+	header := splitRow(rows[0])
+	delim := splitRow(rows[1])
+	for _, c := range delim {
+		if strings.Trim(c, ":- ") != "" || !strings.Contains(c, "-") {
+			return "table has no delimiter row"
+		}
+	}
+	if len(delim) != len(header) {
+		return fmt.Sprintf("delimiter row has %d cells, header has %d", len(delim), len(header))
+	}
+	for i, r := range rows[2:] {
+		if n := len(splitRow(r)); n != len(header) {
+			return fmt.Sprintf("row %d has %d cells, header has %d", i+1, n, len(header))
+		}
+	}
+	return ""
+}
+
+// splitRow splits a pipe table row into cells, honouring escaped pipes and
+// pipes inside code spans.
+func splitRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+	var (
+		cells  []string
+		cur    strings.Builder
+		inCode bool
+	)
+	for i := 0; i < len(row); i++ {
+		c := row[i]
+		switch {
+		case c == '\\' && i+1 < len(row):
+			cur.WriteByte(c)
+			cur.WriteByte(row[i+1])
+			i++
+		case c == '`':
+			inCode = !inCode
+			cur.WriteByte(c)
+		case c == '|' && !inCode:
+			cells = append(cells, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	return append(cells, strings.TrimSpace(cur.String()))
+}
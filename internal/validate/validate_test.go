@@ -0,0 +1,83 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"maplestory-world-llms-txt/internal/document"
+)
+
+const base = "https://maplestoryworlds-creators.nexon.com/en/docs/?postId="
+
+func doc(id, title, md string) document.Document {
+	d := document.New(title, base+id, "<p>x</p>")
+	d.Markdown = md
+	return d
+}
+
+func rules(issues []Issue) map[Rule]int {
+	m := make(map[Rule]int)
+	for _, i := range issues {
+		m[i.Rule]++
+	}
+	return m
+}
+
+func TestCheck_CleanDocument(t *testing.T) {
+	md := "# Workspace\n\nThe Workspace shows every resource of the world in a tree.\n\n| a | b |\n| --- | --- |\n| `x \\| y` | z |\n\n```lua\nprint(1)\n```\n"
+	if issues := NewValidator().Check([]document.Document{doc("1", "Workspace", md)}); len(issues) != 0 {
+		t.Fatalf("expected no issues, got %v", issues)
+	}
+}
+
+func TestCheck_Rules(t *testing.T) {
+	long := strings.Repeat("word ", 40)
+	docs := []document.Document{
+		doc("1", "", "# \n\n"),
+		doc("2", "Short", "# Short\n\nTiny."),
+		doc("3", "Broken", "# Broken\n\n"+long+"\n\n| a | b |\n| --- | --- |\n| only |\n\n<div class=\"x\">left</div>\n\n```\nnever closed"),
+		doc("4", "broken", "# broken\n\n"+long),
+		doc("5", "Code", "# Code\n\n"+long+" `<br>` is fine in code."),
+	}
+	issues := NewValidator().Check(docs)
+	got := rules(issues)
+	want := map[Rule]int{
+		RuleMissingTitle: 1, RuleEmptyBody: 1, RuleShortBody: 1,
+		RuleBrokenTable: 1, RuleRawHTML: 1, RuleUnclosedFence: 1, RuleDuplicateTitle: 1,
+	}
+	for r, n := range want {
+		if got[r] != n {
+			t.Errorf("rule %s: got %d issues, want %d (%v)", r, got[r], n, issues)
+		}
+	}
+	if len(issues) != 7 {
+		t.Errorf("unexpected extra issues: %v", issues)
+	}
+	if !HasErrors(issues) {
+		t.Fatalf("expected errors")
+	}
+	for _, i := range issues {
+		if i.Rule == RuleUnclosedFence && i.Line != 11 {
+			t.Errorf("unclosed fence reported on line %d, want 11", i.Line)
+		}
+	}
+}
+
+func TestCheck_Shrunk(t *testing.T) {
+	prev := doc("1", "Page", "# Page\n\n"+strings.Repeat("word ", 100))
+	cur := doc("1", "Page", "# Page\n\n"+strings.Repeat("word ", 20))
+	issues := NewValidator(WithPrevious([]document.Document{prev})).Check([]document.Document{cur})
+	if len(issues) != 1 || issues[0].Rule != RuleShrunk || HasErrors(issues) {
+		t.Fatalf("expected a single shrunk warning, got %v", issues)
+	}
+	if issues := NewValidator(WithPrevious([]document.Document{prev}), WithShrinkRatio(0)).Check([]document.Document{cur}); len(issues) != 0 {
+		t.Fatalf("shrink check should be disabled, got %v", issues)
+	}
+}
+
+func TestBodyLength_UsesHTMLBeforeConversion(t *testing.T) {
+	d := document.New("T", base+"1", "<h1>T</h1><p>Hello  world</p>")
+	if n := BodyLength(d); n != len("THelloworld") {
+		t.Fatalf("BodyLength = %d", n)
+	}
+}